@@ -0,0 +1,260 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// 未設定 CONSTRUCTION_ARCHIVE_FILE 時的歷史資料庫檔案
+const defaultConstructionArchiveFile = "construction_archive.db"
+
+// 統計排行最多列出的項目數
+const constructionTrendLimit = 5
+
+// 未指定期間時的統計範圍
+const defaultConstructionTrendPeriod = "近90天"
+
+var (
+	// 地點開頭的行政區，例如 "大安區"、"竹北市"
+	caseDistrictPattern = regexp.MustCompile(`^\p{Han}{1,3}?[區鄉鎮市]`)
+	// 地點中的道路名稱，例如 "忠孝東路"、"中正大道"
+	caseRoadPattern = regexp.MustCompile(`\p{Han}{1,8}?(大道|路|街)`)
+	// 統計期間，例如 "近30天"
+	trendDaysPattern = regexp.MustCompile(`^(?:近|最近|過去)(\d+)天$`)
+)
+
+// ArchivedCase 為歷史資料庫中的一筆案件，記錄第一次與最後一次在來源中看到的時間。
+type ArchivedCase struct {
+	Case      ConstructionCase `json:"case"`
+	FirstSeen time.Time        `json:"firstSeen"`
+	LastSeen  time.Time        `json:"lastSeen"`
+}
+
+// ActivePeriod 回傳案件的施工期間，來源沒有提供的一端以在來源中出現的時間代替。
+func (a ArchivedCase) ActivePeriod() (time.Time, time.Time) {
+	start, end := a.Case.Start, a.Case.End
+	if start.IsZero() {
+		start = a.FirstSeen
+	}
+	if end.IsZero() {
+		end = a.LastSeen
+	}
+	return start, end
+}
+
+// ConstructionArchive 以 bbolt 保存所有看過的施工案件，每個縣市一個 bucket，key 為 ConstructionCase.Key。
+type ConstructionArchive struct {
+	db *bolt.DB
+}
+
+// constructionArchive 於 main 開啟，開啟失敗時為 nil
+var constructionArchive *ConstructionArchive
+
+// OpenConstructionArchive 開啟或建立歷史資料庫。
+func OpenConstructionArchive(path string) (*ConstructionArchive, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("開啟施工歷史資料庫失敗: %w", err)
+	}
+	return &ConstructionArchive{db: db}, nil
+}
+
+// Close 關閉資料庫。
+func (a *ConstructionArchive) Close() error {
+	return a.db.Close()
+}
+
+// Record 可註冊為快取的 SnapshotListener，每次更新後將案件寫入歷史資料庫。
+func (a *ConstructionArchive) Record(previous, current ConstructionSnapshot, hasPrevious bool) {
+	if err := a.Save(current); err != nil {
+		log.Printf("寫入 %s 施工歷史資料失敗: %v", current.County, err)
+	}
+}
+
+// Save 寫入快照中的案件，已存在的案件保留第一次看到的時間並更新其餘欄位。
+func (a *ConstructionArchive) Save(snapshot ConstructionSnapshot) error {
+	return a.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(snapshot.County))
+		if err != nil {
+			return err
+		}
+		for _, c := range snapshot.Cases {
+			key := []byte(c.Key())
+			record := ArchivedCase{Case: c, FirstSeen: snapshot.UpdatedAt, LastSeen: snapshot.UpdatedAt}
+			// 原始欄位只用於除錯，不保存以節省空間
+			record.Case.Raw = nil
+			if data := bucket.Get(key); data != nil {
+				var existing ArchivedCase
+				if err := json.Unmarshal(data, &existing); err == nil && existing.FirstSeen.Before(record.FirstSeen) {
+					record.FirstSeen = existing.FirstSeen
+				}
+			}
+			data, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(key, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Cases 回傳縣市在 [from, to) 期間內施工的歷史案件。
+func (a *ConstructionArchive) Cases(county string, from, to time.Time) ([]ArchivedCase, error) {
+	var cases []ArchivedCase
+	err := a.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(county))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			var record ArchivedCase
+			if err := json.Unmarshal(v, &record); err != nil {
+				return fmt.Errorf("解析歷史案件 %s 失敗: %w", k, err)
+			}
+			start, end := record.ActivePeriod()
+			if start.Before(to) && !end.Before(from) {
+				cases = append(cases, record)
+			}
+			return nil
+		})
+	})
+	return cases, err
+}
+
+// TrendItem 為統計排行中的一個項目。
+type TrendItem struct {
+	Name  string
+	Count int
+}
+
+// ConstructionTrend 為一段期間內的施工統計。
+type ConstructionTrend struct {
+	County    string
+	Period    string // 顯示用的期間，例如 "近90天"
+	From      time.Time
+	To        time.Time
+	Total     int
+	Districts []TrendItem // 各行政區施工中的案件數，由多到少
+	Roads     []TrendItem // 各道路施工的案件數，由多到少
+}
+
+// Trend 統計縣市在期間內施工的案件數，依行政區與道路排行。
+func (a *ConstructionArchive) Trend(county, period string, now time.Time) (ConstructionTrend, error) {
+	from, to, ok := parseTrendPeriod(period, now)
+	if !ok {
+		return ConstructionTrend{}, fmt.Errorf("無法辨識統計期間「%s」", period)
+	}
+	cases, err := a.Cases(county, from, to)
+	if err != nil {
+		return ConstructionTrend{}, err
+	}
+
+	districts := map[string]int{}
+	roads := map[string]int{}
+	for _, record := range cases {
+		district, road := caseDistrictAndRoad(county, record.Case)
+		if district != "" {
+			districts[district]++
+		}
+		if road != "" {
+			roads[road]++
+		}
+	}
+	return ConstructionTrend{
+		County:    county,
+		Period:    period,
+		From:      from,
+		To:        to,
+		Total:     len(cases),
+		Districts: rankTrendItems(districts),
+		Roads:     rankTrendItems(roads),
+	}, nil
+}
+
+func (t ConstructionTrend) String() string {
+	if t.Total == 0 {
+		return fmt.Sprintf("%s%s沒有施工紀錄", t.County, t.Period)
+	}
+
+	lines := []string{
+		fmt.Sprintf("%s施工統計 (%s)", t.County, t.Period),
+		fmt.Sprintf("期間內施工案件: %d 件", t.Total),
+	}
+	writeItems := func(title string, items []TrendItem) {
+		if len(items) == 0 {
+			return
+		}
+		lines = append(lines, "", title)
+		for i, item := range items {
+			lines = append(lines, fmt.Sprintf("%d. %s %d 件", i+1, item.Name, item.Count))
+		}
+	}
+	writeItems("行政區施工案件數:", t.Districts)
+	writeItems("最常施工的道路:", t.Roads)
+	return strings.Join(lines, "\n")
+}
+
+// parseTrendPeriod 解析統計期間，支援 "近N天"、"本月"、"今年"。
+func parseTrendPeriod(period string, now time.Time) (from, to time.Time, ok bool) {
+	now = now.In(taipeiLocation)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, taipeiLocation)
+	tomorrow := today.AddDate(0, 0, 1)
+	switch period {
+	case "本月":
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, taipeiLocation), tomorrow, true
+	case "今年":
+		return time.Date(now.Year(), 1, 1, 0, 0, 0, 0, taipeiLocation), tomorrow, true
+	}
+	if m := trendDaysPattern.FindStringSubmatch(period); m != nil {
+		days, err := strconv.Atoi(m[1])
+		if err == nil && days > 0 && days <= 3660 {
+			return tomorrow.AddDate(0, 0, -days), tomorrow, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// caseDistrictAndRoad 從案件地點取出行政區與道路名稱，無法辨識的部分為空字串。
+func caseDistrictAndRoad(county string, c ConstructionCase) (district, road string) {
+	location := normalizeAddress(c.Location)
+	names := []string{county}
+	if source, ok := LookupConstructionSource(county); ok {
+		names = append(names, source.Aliases()...)
+	}
+	for _, name := range names {
+		location = strings.TrimPrefix(location, normalizeAddress(name))
+	}
+
+	district = caseDistrictPattern.FindString(location)
+	location = strings.TrimPrefix(location, district)
+	road = caseRoadPattern.FindString(location)
+	return district, road
+}
+
+func rankTrendItems(counts map[string]int) []TrendItem {
+	items := make([]TrendItem, 0, len(counts))
+	for name, count := range counts {
+		items = append(items, TrendItem{Name: name, Count: count})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Name < items[j].Name
+	})
+	if len(items) > constructionTrendLimit {
+		items = items[:constructionTrendLimit]
+	}
+	return items
+}
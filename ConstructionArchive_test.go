@@ -0,0 +1,135 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestConstructionArchiveTrend(t *testing.T) {
+	archive, err := OpenConstructionArchive(filepath.Join(t.TempDir(), "archive.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+
+	october, december1, december20 := taipeiDate(2024, 10, 1, 8, 0), taipeiDate(2024, 12, 1, 8, 0), taipeiDate(2024, 12, 20, 8, 0)
+	a := ConstructionCase{County: "台北市", CaseID: "A", Location: "大安區忠孝東路四段1號",
+		Start: taipeiDate(2024, 12, 1, 0, 0), End: taipeiDate(2024, 12, 10, 0, 0)}
+	b := ConstructionCase{County: "台北市", CaseID: "B", Location: "臺北市信義區信義路五段7號",
+		Start: taipeiDate(2024, 12, 1, 0, 0), End: taipeiDate(2024, 12, 5, 0, 0)}
+	// 沒有施工期間，以在來源中出現的時間代替
+	c := ConstructionCase{County: "台北市", CaseID: "C", Location: "大安區忠孝東路一段"}
+	d := ConstructionCase{County: "台北市", CaseID: "D", Location: "中山區中山北路二段",
+		Start: taipeiDate(2024, 12, 18, 0, 0), End: taipeiDate(2024, 12, 31, 0, 0)}
+	e := ConstructionCase{County: "台北市", CaseID: "E", Location: "中山區中山北路三段",
+		Start: taipeiDate(2024, 10, 1, 0, 0), End: taipeiDate(2024, 10, 5, 0, 0)}
+	f := ConstructionCase{County: "台北市", CaseID: "F", Location: "市民大道三段"}
+
+	snapshots := []ConstructionSnapshot{
+		{County: "台北市", UpdatedAt: october, Cases: []ConstructionCase{e, f}},
+		{County: "台北市", UpdatedAt: december1, Cases: []ConstructionCase{a, b, c}},
+		{County: "台北市", UpdatedAt: december20, Cases: []ConstructionCase{a, c, d}},
+	}
+	for _, snapshot := range snapshots {
+		if err := archive.Save(snapshot); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases, err := archive.Cases("台北市", taipeiDate(2024, 1, 1, 0, 0), taipeiDate(2025, 1, 1, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := map[string]ArchivedCase{}
+	for _, record := range cases {
+		byID[record.Case.CaseID] = record
+	}
+	if len(byID) != 6 {
+		t.Fatalf("archived %d cases, want 6", len(byID))
+	}
+	// 再次出現的案件保留第一次看到的時間
+	if got := byID["A"]; !got.FirstSeen.Equal(december1) || !got.LastSeen.Equal(december20) {
+		t.Errorf("A seen %v ~ %v, want %v ~ %v", got.FirstSeen, got.LastSeen, december1, december20)
+	}
+	if start, end := byID["C"].ActivePeriod(); !start.Equal(december1) || !end.Equal(december20) {
+		t.Errorf("C active %v ~ %v, want %v ~ %v", start, end, december1, december20)
+	}
+
+	now := taipeiDate(2024, 12, 25, 9, 0)
+	trend, err := archive.Trend("台北市", "近30天", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// E 在十月結束，F 只在十月出現過
+	if trend.Total != 4 {
+		t.Errorf("total = %d, want 4", trend.Total)
+	}
+	wantDistricts := []TrendItem{{"大安區", 2}, {"中山區", 1}, {"信義區", 1}}
+	if !reflect.DeepEqual(trend.Districts, wantDistricts) {
+		t.Errorf("districts = %v, want %v", trend.Districts, wantDistricts)
+	}
+	wantRoads := []TrendItem{{"忠孝東路", 2}, {"中山北路", 1}, {"信義路", 1}}
+	if !reflect.DeepEqual(trend.Roads, wantRoads) {
+		t.Errorf("roads = %v, want %v", trend.Roads, wantRoads)
+	}
+
+	trend, err = archive.Trend("台北市", "今年", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRoads = []TrendItem{{"中山北路", 2}, {"忠孝東路", 2}, {"信義路", 1}, {"市民大道", 1}}
+	if trend.Total != 6 || !reflect.DeepEqual(trend.Roads, wantRoads) {
+		t.Errorf("今年 total = %d, roads = %v; want 6, %v", trend.Total, trend.Roads, wantRoads)
+	}
+}
+
+func TestParseTrendPeriod(t *testing.T) {
+	now := taipeiDate(2024, 12, 25, 9, 0)
+	tomorrow := taipeiDate(2024, 12, 26, 0, 0)
+	tests := []struct {
+		period string
+		from   string
+		ok     bool
+	}{
+		{"近30天", "2024-11-26", true},
+		{"最近7天", "2024-12-19", true},
+		{"本月", "2024-12-01", true},
+		{"今年", "2024-01-01", true},
+		{"近0天", "", false},
+		{"上週", "", false},
+	}
+	for _, tt := range tests {
+		from, to, ok := parseTrendPeriod(tt.period, now)
+		if ok != tt.ok {
+			t.Errorf("%s: ok = %v, want %v", tt.period, ok, tt.ok)
+			continue
+		}
+		if ok && (from.Format("2006-01-02") != tt.from || !to.Equal(tomorrow)) {
+			t.Errorf("%s = %v ~ %v, want %s ~ %v", tt.period, from, to, tt.from, tomorrow)
+		}
+	}
+}
+
+func TestCaseDistrictAndRoad(t *testing.T) {
+	tests := []struct {
+		county, location string
+		district, road   string
+	}{
+		{"台北市", "大安區忠孝東路四段181號前", "大安區", "忠孝東路"},
+		{"台北市", "臺北市信義區信義路五段7號", "信義區", "信義路"},
+		{"台中市", "中區中山路", "中區", "中山路"},
+		{"新竹縣", "竹北市光明六路10號", "竹北市", "光明六路"},
+		{"花蓮縣", "吉安鄉中華路二段", "吉安鄉", "中華路"},
+		{"高雄市", "苓雅區中正大道", "苓雅區", "中正大道"},
+		{"台北市", "市民大道三段", "", "市民大道"},
+		{"金門縣", "金城鎮民生街", "金城鎮", "民生街"},
+		{"台北市", "捷運站出口", "", ""},
+	}
+	for _, tt := range tests {
+		district, road := caseDistrictAndRoad(tt.county, ConstructionCase{Location: tt.location})
+		if district != tt.district || road != tt.road {
+			t.Errorf("%s %s = %q, %q; want %q, %q", tt.county, tt.location, district, road, tt.district, tt.road)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// 未指定時各來源的背景更新間隔
+const defaultConstructionRefreshInterval = 15 * time.Minute
+
+// refreshIntervaler 可由資料來源選擇實作，以使用不同於預設值的更新間隔。
+type refreshIntervaler interface {
+	RefreshInterval() time.Duration
+}
+
+// fetchTimeouter 可由資料來源選擇實作，需要多次請求才能取得完整資料的來源可延長抓取時間上限。
+type fetchTimeouter interface {
+	FetchTimeout() time.Duration
+}
+
+// ConstructionSnapshot 為某縣市最後一次成功取得的施工資料。
+type ConstructionSnapshot struct {
+	County    string             `json:"county"`
+	Cases     []ConstructionCase `json:"cases"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+// SourceStatus 記錄資料來源最近一次成功與失敗的狀態。
+type SourceStatus struct {
+	County      string    `json:"county"`
+	LastSuccess time.Time `json:"lastSuccess"`
+	LastError   string    `json:"lastError,omitempty"`
+	LastErrorAt time.Time `json:"lastErrorAt"`
+	CaseCount   int       `json:"caseCount"`
+	// SchemaDrift 表示最近一次失敗是因為網頁結構改變
+	SchemaDrift bool `json:"schemaDrift"`
+}
+
+// Stale 表示最近一次更新失敗，目前提供的是先前的資料。
+func (s SourceStatus) Stale() bool {
+	return s.LastErrorAt.After(s.LastSuccess)
+}
+
+// errSnapshotPending 表示快取中還沒有資料，且第一次抓取未在查詢的時間上限內完成
+var errSnapshotPending = errors.New("施工資料更新中")
+
+// ConstructionCache 在背景定期更新各來源，並保留最後一次成功的資料。
+type ConstructionCache struct {
+	mu         sync.RWMutex
+	snapshots  map[string]ConstructionSnapshot
+	status     map[string]SourceStatus
+	refreshing map[string]*refreshCall // 進行中的更新，同一來源同時只抓取一次
+	dir        string                  // 磁碟快取目錄，空字串表示只保存在記憶體
+	listeners  []SnapshotListener
+}
+
+// refreshCall 為一次進行中的更新，done 在更新結束後關閉。
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+// SnapshotListener 在縣市資料更新成功後被呼叫，hasPrevious 為 false 表示先前沒有資料。
+type SnapshotListener func(previous, current ConstructionSnapshot, hasPrevious bool)
+
+var constructionCache = NewConstructionCache(os.Getenv("CONSTRUCTION_CACHE_DIR"))
+
+// NewConstructionCache 建立快取，dir 不為空時會載入並寫入該目錄下的快照檔。
+func NewConstructionCache(dir string) *ConstructionCache {
+	c := &ConstructionCache{
+		snapshots:  map[string]ConstructionSnapshot{},
+		status:     map[string]SourceStatus{},
+		refreshing: map[string]*refreshCall{},
+		dir:        dir,
+	}
+	c.load()
+	return c
+}
+
+// OnUpdate 註冊資料更新後要執行的函式，需在 Start 之前呼叫。
+func (c *ConstructionCache) OnUpdate(listener SnapshotListener) {
+	c.listeners = append(c.listeners, listener)
+}
+
+// Start 為每個已註冊的來源啟動背景更新，ctx 結束時停止。
+func (c *ConstructionCache) Start(ctx context.Context) {
+	for _, source := range ConstructionSources() {
+		go c.poll(ctx, source)
+	}
+}
+
+func (c *ConstructionCache) poll(ctx context.Context, source ConstructionSource) {
+	interval := constructionRefreshInterval(source)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := c.Refresh(ctx, source)
+		var drift *SchemaDriftError
+		switch {
+		case errors.As(err, &drift):
+			log.Printf("[ALERT] %v", err)
+		case err != nil:
+			log.Printf("更新 %s 施工資料失敗: %v", source.County(), err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh 立即向來源取得資料，成功時取代快照，失敗時保留舊資料並記錄錯誤。
+// 同一來源已有更新進行中時會等待該次結果。抓取在背景以來源自己的時間上限執行，
+// ctx 結束只會停止等待，不會中斷抓取，因此需要逐頁翻頁的來源不會被使用者查詢的時間上限截斷。
+func (c *ConstructionCache) Refresh(ctx context.Context, source ConstructionSource) error {
+	county := source.County()
+	c.mu.Lock()
+	call, ok := c.refreshing[county]
+	if !ok {
+		call = &refreshCall{done: make(chan struct{})}
+		c.refreshing[county] = call
+		go func() {
+			call.err = c.refresh(context.WithoutCancel(ctx), source)
+			c.mu.Lock()
+			delete(c.refreshing, county)
+			c.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *ConstructionCache) refresh(ctx context.Context, source ConstructionSource) error {
+	ctx, cancel := context.WithTimeout(ctx, constructionFetchTimeoutFor(source))
+	defer cancel()
+
+	cases, err := fetchConstructionCases(ctx, source)
+	now := time.Now()
+	county := source.County()
+
+	c.mu.Lock()
+	status := c.status[county]
+	status.County = county
+	if err != nil {
+		var drift *SchemaDriftError
+		status.SchemaDrift = errors.As(err, &drift)
+		status.LastError = err.Error()
+		status.LastErrorAt = now
+		c.status[county] = status
+		c.mu.Unlock()
+		return err
+	}
+	snapshot := ConstructionSnapshot{County: county, Cases: cases, UpdatedAt: now}
+	previous, hasPrevious := c.snapshots[county]
+	c.snapshots[county] = snapshot
+	status.LastSuccess = now
+	status.CaseCount = len(cases)
+	status.SchemaDrift = false
+	c.status[county] = status
+	c.mu.Unlock()
+
+	c.save(snapshot)
+	for _, listener := range c.listeners {
+		listener(previous, snapshot, hasPrevious)
+	}
+	return nil
+}
+
+// Get 回傳縣市目前的快照與來源狀態。
+func (c *ConstructionCache) Get(county string) (ConstructionSnapshot, SourceStatus, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	snapshot, ok := c.snapshots[county]
+	return snapshot, c.status[county], ok
+}
+
+// Status 回傳所有已註冊來源的狀態，依縣市名稱排序。
+func (c *ConstructionCache) Status() []SourceStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var statuses []SourceStatus
+	for _, source := range ConstructionSources() {
+		status := c.status[source.County()]
+		status.County = source.County()
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Snapshot 取得縣市資料，快取中還沒有資料時會向來源抓取一次並等待到 ctx 結束，
+// 逾時時回傳 errSnapshotPending，抓取仍會在背景完成。
+func (c *ConstructionCache) Snapshot(ctx context.Context, source ConstructionSource) (ConstructionSnapshot, SourceStatus, error) {
+	if snapshot, status, ok := c.Get(source.County()); ok {
+		return snapshot, status, nil
+	}
+	if err := c.Refresh(ctx, source); err != nil {
+		if ctx.Err() != nil {
+			return ConstructionSnapshot{}, SourceStatus{}, errSnapshotPending
+		}
+		return ConstructionSnapshot{}, SourceStatus{}, err
+	}
+	snapshot, status, _ := c.Get(source.County())
+	return snapshot, status, nil
+}
+
+func (c *ConstructionCache) snapshotPath(county string) string {
+	return filepath.Join(c.dir, county+".json")
+}
+
+func (c *ConstructionCache) save(snapshot ConstructionSnapshot) {
+	if c.dir == "" {
+		return
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		log.Printf("序列化 %s 快照失敗: %v", snapshot.County, err)
+		return
+	}
+	// 先寫入暫存檔再改名，避免程式中斷時留下不完整的檔案
+	path := c.snapshotPath(snapshot.County)
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		log.Printf("寫入 %s 快照失敗: %v", snapshot.County, err)
+		return
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		log.Printf("寫入 %s 快照失敗: %v", snapshot.County, err)
+	}
+}
+
+func (c *ConstructionCache) load() {
+	if c.dir == "" {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		log.Printf("建立快取目錄失敗: %v", err)
+		return
+	}
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		log.Printf("讀取快取目錄失敗: %v", err)
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(c.dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var snapshot ConstructionSnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil || snapshot.County == "" {
+			log.Printf("讀取快照 %s 失敗: %v", entry.Name(), err)
+			continue
+		}
+		c.snapshots[snapshot.County] = snapshot
+		c.status[snapshot.County] = SourceStatus{
+			County:      snapshot.County,
+			LastSuccess: snapshot.UpdatedAt,
+			CaseCount:   len(snapshot.Cases),
+		}
+	}
+}
+
+func constructionRefreshInterval(source ConstructionSource) time.Duration {
+	if s, ok := source.(refreshIntervaler); ok && s.RefreshInterval() > 0 {
+		return s.RefreshInterval()
+	}
+	if d, err := time.ParseDuration(os.Getenv("CONSTRUCTION_REFRESH_INTERVAL")); err == nil && d > 0 {
+		return d
+	}
+	return defaultConstructionRefreshInterval
+}
+
+func constructionFetchTimeoutFor(source ConstructionSource) time.Duration {
+	if s, ok := source.(fetchTimeouter); ok && s.FetchTimeout() > 0 {
+		return s.FetchTimeout()
+	}
+	return constructionFetchTimeout
+}
+
+// formatUpdatedAt 以台北時間顯示資料更新時間
+func formatUpdatedAt(t time.Time) string {
+	return fmt.Sprintf("資料更新時間: %s", t.In(taipeiLocation).Format("2006/01/02 15:04"))
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowSource 在 release 關閉前不會回傳，用來模擬需要逐頁翻頁的來源。
+type slowSource struct {
+	release chan struct{}
+	fetches *int32
+}
+
+func (slowSource) County() string    { return "花蓮縣" }
+func (slowSource) Aliases() []string { return nil }
+
+func (s slowSource) Fetch(ctx context.Context) ([]ConstructionCase, error) {
+	atomic.AddInt32(s.fetches, 1)
+	select {
+	case <-s.release:
+		return []ConstructionCase{{CaseID: "HL1"}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestSnapshotColdCacheDoesNotBlock(t *testing.T) {
+	cache := NewConstructionCache("")
+	source := slowSource{release: make(chan struct{}), fetches: new(int32)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := cache.Snapshot(ctx, source); !errors.Is(err, errSnapshotPending) {
+		t.Fatalf("Snapshot on cold cache = %v, want errSnapshotPending", err)
+	}
+	if got := constructionErrorMessage(errSnapshotPending); got != "施工資料正在更新中，請稍後再試" {
+		t.Errorf("message = %q", got)
+	}
+
+	// 第二次查詢沿用進行中的抓取，不會重複向來源請求
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := cache.Snapshot(context.Background(), source)
+		done <- err
+	}()
+	close(source.release)
+	if err := <-done; err != nil {
+		t.Fatalf("Snapshot after fetch finished: %v", err)
+	}
+	if n := atomic.LoadInt32(source.fetches); n != 1 {
+		t.Errorf("source fetched %d times, want 1", n)
+	}
+	if snapshot, _, ok := cache.Get("花蓮縣"); !ok || len(snapshot.Cases) != 1 {
+		t.Errorf("cached snapshot = %+v, %v", snapshot, ok)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ConstructionCase 為各縣市來源正規化後的施工案件，
+// 篩選、排序、顯示與匯出都只依賴這個結構。
+type ConstructionCase struct {
+	County    string               `json:"county"`    // 縣市名稱
+	CaseID    string               `json:"caseId"`    // 案件編號或許可證字號
+	Agency    string               `json:"agency"`    // 申請或施工單位
+	Reason    string               `json:"reason"`    // 施工原因或工程名稱
+	Location  string               `json:"location"`  // 施工地點文字
+	Start     time.Time            `json:"start"`     // 施工起日，未知時為零值
+	End       time.Time            `json:"end"`       // 施工迄日，未知時為零值
+	Category  string               `json:"category"`  // 來源的通報類別名稱
+	Kind      ConstructionCategory `json:"kind"`      // 跨縣市共用的類別
+	SourceURL string               `json:"sourceUrl"` // 資料來源網址
+	Raw       map[string]string    `json:"raw"`       // 來源原始欄位
+}
+
+// Key 回傳可用來比對同一案件的識別值，沒有案件編號時以地點與期間雜湊產生。
+func (c ConstructionCase) Key() string {
+	if c.CaseID != "" {
+		return c.County + ":" + c.CaseID
+	}
+	sum := sha1.Sum([]byte(strings.Join([]string{
+		c.Location, c.Reason, c.Start.Format("20060102"), c.End.Format("20060102"),
+	}, "|")))
+	return c.County + ":" + hex.EncodeToString(sum[:8])
+}
+
+// Period 回傳民國年格式的施工期間，例如 "113年12月25日至113年12月31日"。
+func (c ConstructionCase) Period() string {
+	switch {
+	case c.Start.IsZero() && c.End.IsZero():
+		return "無資料"
+	case c.End.IsZero():
+		return formatROCDate(c.Start) + "起"
+	case c.Start.IsZero():
+		return "至" + formatROCDate(c.End)
+	}
+	return formatROCDate(c.Start) + "至" + formatROCDate(c.End)
+}
+
+func (c ConstructionCase) String() string {
+	var sb strings.Builder
+	writeField := func(label, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", label, value))
+		}
+	}
+	writeField("案件編號", c.CaseID)
+	writeField("名稱", c.Reason)
+	writeField("單位", c.Agency)
+	location := c.Location
+	if strings.TrimSpace(location) == "" {
+		location = "無地點資料"
+	}
+	writeField("地點", location)
+	writeField("期間", c.Period())
+	writeField("類別", c.Category)
+	return sb.String()
+}
+
+func formatROCDate(t time.Time) string {
+	return fmt.Sprintf("%d年%02d月%02d日", t.Year()-1911, t.Month(), t.Day())
+}
+
+// parseCaseTime 以 ParseTaiwanDate 解析單一日期，解析失敗時回傳零值。
+func parseCaseTime(s string) time.Time {
+	t, _ := ParseTaiwanDate(s)
+	return t
+}
+
+// parseCasePeriod 以 ParseTaiwanDateRange 解析期間文字，解析失敗的一端為零值。
+func parseCasePeriod(period string) (time.Time, time.Time) {
+	start, end, _ := ParseTaiwanDateRange(period)
+	return start, end
+}
+
+// ActiveAt 判斷案件在 t 時是否在施工期間內，只有日期的迄日視為當天整天。
+// 期間未知的一端視為不設限。
+func (c ConstructionCase) ActiveAt(t time.Time) bool {
+	if !c.Start.IsZero() && t.Before(c.Start) {
+		return false
+	}
+	if c.End.IsZero() {
+		return true
+	}
+	return t.Before(c.effectiveEnd())
+}
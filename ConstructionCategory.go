@@ -0,0 +1,99 @@
+package main
+
+import "strings"
+
+// ConstructionCategory 為跨縣市共用的施工類別，各來源將自己的類別代碼對應到這裡。
+type ConstructionCategory string
+
+const (
+	CategoryPlanned     ConstructionCategory = "planned"     // 一般計畫性施工
+	CategoryEmergency   ConstructionCategory = "emergency"   // 緊急搶修
+	CategoryResurfacing ConstructionCategory = "resurfacing" // 路面銑鋪與維護
+	CategoryManhole     ConstructionCategory = "manhole"     // 管線人手孔施工
+	CategoryRestoration ConstructionCategory = "restoration" // 建案公設復舊
+)
+
+// constructionCategoryLabels 為各類別顯示的名稱
+var constructionCategoryLabels = map[ConstructionCategory]string{
+	CategoryPlanned:     "一般施工",
+	CategoryEmergency:   "搶修",
+	CategoryResurfacing: "路面銑鋪",
+	CategoryManhole:     "人手孔施工",
+	CategoryRestoration: "建案復舊",
+}
+
+// constructionCategoryColors 為卡片標頭的顏色，搶修以紅色凸顯
+var constructionCategoryColors = map[ConstructionCategory]string{
+	CategoryPlanned:     "#E67E22",
+	CategoryEmergency:   "#C0392B",
+	CategoryResurfacing: "#7F8C8D",
+	CategoryManhole:     "#2980B9",
+	CategoryRestoration: "#8E44AD",
+}
+
+// constructionCategoryWords 為指令中可用來篩選類別的詞
+var constructionCategoryWords = map[string]ConstructionCategory{
+	"一般施工":  CategoryPlanned,
+	"計畫施工":  CategoryPlanned,
+	"搶修":    CategoryEmergency,
+	"緊急搶修":  CategoryEmergency,
+	"銑鋪":    CategoryResurfacing,
+	"路面銑鋪":  CategoryResurfacing,
+	"刨鋪":    CategoryResurfacing,
+	"人手孔":   CategoryManhole,
+	"人手孔施工": CategoryManhole,
+	"復舊":    CategoryRestoration,
+	"建案復舊":  CategoryRestoration,
+}
+
+// 沒有類別代碼的來源以案件名稱與類別文字中的關鍵字推斷類別，依序比對
+var constructionCategoryKeywords = []struct {
+	category ConstructionCategory
+	keywords []string
+}{
+	{CategoryEmergency, []string{"搶修", "緊急", "漏水", "災修"}},
+	{CategoryManhole, []string{"人手孔", "人孔", "手孔"}},
+	{CategoryRestoration, []string{"復舊", "建案", "公設"}},
+	{CategoryResurfacing, []string{"銑鋪", "刨鋪", "刨除", "重鋪", "路面改善", "道路維護"}},
+}
+
+// Label 回傳類別的中文名稱。
+func (c ConstructionCategory) Label() string {
+	if label, ok := constructionCategoryLabels[c]; ok {
+		return label
+	}
+	return constructionCategoryLabels[CategoryPlanned]
+}
+
+// parseConstructionCategory 將指令中的類別詞轉為類別，不是類別詞時回傳 false。
+func parseConstructionCategory(word string) (ConstructionCategory, bool) {
+	category, ok := constructionCategoryWords[word]
+	if !ok {
+		// 也接受匯出 API 使用的英文代碼
+		if _, known := constructionCategoryLabels[ConstructionCategory(word)]; known {
+			return ConstructionCategory(word), true
+		}
+	}
+	return category, ok
+}
+
+// classifyConstruction 依文字中的關鍵字推斷類別，沒有符合時視為一般施工。
+func classifyConstruction(texts ...string) ConstructionCategory {
+	joined := strings.Join(texts, "\n")
+	for _, rule := range constructionCategoryKeywords {
+		for _, keyword := range rule.keywords {
+			if strings.Contains(joined, keyword) {
+				return rule.category
+			}
+		}
+	}
+	return CategoryPlanned
+}
+
+// caseCategory 回傳案件的類別，來源未對應類別時 (包含舊版快取) 依文字推斷。
+func caseCategory(c ConstructionCase) ConstructionCategory {
+	if c.Kind != "" {
+		return c.Kind
+	}
+	return classifyConstruction(c.Category, c.Reason)
+}
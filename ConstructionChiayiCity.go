@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"net/http"
+)
+
+const chiayiCityConstructionURL = "https://data.chiayi.gov.tw/opendata/api/getResource?oid=2cf1aa4f-3cdd-46a0-be84-b6f161cd892d&rid=ca4c025d-1856-4200-81b7-a401aa653da3"
+
+type chiayiCitySource struct {
+	url string
+}
+
+func init() {
+	RegisterConstructionSource(chiayiCitySource{url: chiayiCityConstructionURL})
+}
+
+func (chiayiCitySource) County() string    { return "嘉義市" }
+func (chiayiCitySource) Aliases() []string { return nil }
+
+func (s chiayiCitySource) Fetch(ctx context.Context) ([]ConstructionCase, error) {
+	// 此網站會擋掉非瀏覽器的 User-Agent
+	header := http.Header{}
+	header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) "+
+		"AppleWebKit/537.36 (KHTML, like Gecko) "+
+		"Chrome/58.0.3029.110 Safari/537.3")
+
+	body, err := upstream.Get(ctx, s.County(), s.url, header)
+	if err != nil {
+		return nil, err
+	}
+	return parseDigCaseXML(body, s.url)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const chiayiCountyConstructionURL = "https://publicpipe.cyhg.gov.tw/ChiayiPub/Report1.aspx"
+
+// 案件列表的 GridView
+const chiayiCountyGrid = "table#ctl00_ContentPlaceHolder1_GridView1"
+
+type chiayiCountySource struct {
+	url string
+}
+
+func init() {
+	RegisterConstructionSource(chiayiCountySource{url: chiayiCountyConstructionURL})
+}
+
+func (chiayiCountySource) County() string    { return "嘉義縣" }
+func (chiayiCountySource) Aliases() []string { return nil }
+
+func (chiayiCountySource) FetchTimeout() time.Duration { return webFormsFetchTimeout }
+
+func (source chiayiCountySource) Fetch(ctx context.Context) ([]ConstructionCase, error) {
+	var cases []ConstructionCase
+	err := webFormsPages(ctx, source.County(), source.url, nil, func(page int, doc *goquery.Document) error {
+		if err := checkPageSchema(source.County(), doc, pageSchema{
+			header:     chiayiCountyGrid + gridHeaderRow,
+			columns:    []string{"", "", "", "日期", "狀態"},
+			rows:       chiayiCountyGrid + gridDataRows,
+			minColumns: 5,
+		}); err != nil {
+			return err
+		}
+
+		doc.Find(chiayiCountyGrid + gridDataRows).Each(func(i int, s *goquery.Selection) {
+			tds := s.Find("td")
+
+			// 提取起點和終點
+			startText := strings.TrimSpace(s.Find("span[id$='LabDigStart']").First().Text())
+			endText := strings.TrimSpace(s.Find("span[id$='LabDigEnd']").First().Text())
+			// 將第二個起點替換為終點
+			endText = strings.Replace(endText, "起點：", "終點：", 1)
+
+			// 提取日期
+			dateText := strings.TrimSpace(tds.Eq(3).Text())
+			start, end := parseCasePeriod(dateText)
+
+			// 提取狀態
+			status := strings.TrimSpace(tds.Eq(4).Text())
+
+			cases = append(cases, ConstructionCase{
+				Location:  strings.TrimSpace(startText + " " + endText),
+				Start:     start,
+				End:       end,
+				SourceURL: source.url,
+				Raw: map[string]string{
+					"起點": startText,
+					"終點": endText,
+					"日期": dateText,
+					"狀態": status,
+				},
+			})
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uniqueCases(cases), nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// parseDigCaseXML 解析各縣市道路挖掘管理系統共用的 <DIG_CASE><CASE_LIST><CASE_DETAIL>... 格式，
+// 所有欄位都會保留在 Raw 中。
+func parseDigCaseXML(body []byte, sourceURL string) ([]ConstructionCase, error) {
+	type element struct {
+		XMLName xml.Name
+		Value   string `xml:",chardata"`
+	}
+
+	var digCase struct {
+		Details []struct {
+			Fields []element `xml:",any"`
+		} `xml:"CASE_LIST>CASE_DETAIL"`
+	}
+	if err := xml.Unmarshal(body, &digCase); err != nil {
+		return nil, fmt.Errorf("解析 XML 失敗: %w", err)
+	}
+
+	var cases []ConstructionCase
+	for _, detail := range digCase.Details {
+		raw := make(map[string]string, len(detail.Fields))
+		for _, field := range detail.Fields {
+			raw[field.XMLName.Local] = strings.TrimSpace(field.Value)
+		}
+
+		cases = append(cases, ConstructionCase{
+			CaseID:    raw["CASE_ID"],
+			Agency:    raw["APP_NAME"],
+			Reason:    raw["CONST_NAME"],
+			Location:  raw["LOCATION"],
+			Start:     parseCaseTime(raw["ABE_DA"]),
+			End:       parseCaseTime(raw["AEN_DA"]),
+			SourceURL: sourceURL,
+			Raw:       raw,
+		})
+	}
+	return cases, nil
+}
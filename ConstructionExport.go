@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// constructionExportHandler 提供 /api/construction，以 json、geojson 或 csv 匯出快取中的正規化施工資料。
+// 參數: county (省略時匯出所有已快取的縣市)、district、keyword (可重複或以空白分隔)、
+// window (今天、本週、未來7天或日期)、category (搶修等類別詞或 emergency 等代碼)、format (預設 json)。
+func constructionExportHandler(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := ConstructionQuery{
+		District: strings.TrimSpace(params.Get("district")),
+		Window:   strings.TrimSpace(params.Get("window")),
+	}
+	for _, keyword := range params["keyword"] {
+		query.Keywords = append(query.Keywords, strings.Fields(keyword)...)
+	}
+	if query.Window != "" && !isDateWindow(query.Window) {
+		http.Error(w, "invalid window", http.StatusBadRequest)
+		return
+	}
+	if category := strings.TrimSpace(params.Get("category")); category != "" {
+		var ok bool
+		if query.Category, ok = parseConstructionCategory(category); !ok {
+			http.Error(w, "invalid category", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var snapshots []ConstructionSnapshot
+	if county := params.Get("county"); county != "" {
+		source, ok := LookupConstructionSource(county)
+		if !ok {
+			http.Error(w, "unknown county", http.StatusBadRequest)
+			return
+		}
+		snapshot, _, err := constructionCache.Snapshot(r.Context(), source)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		snapshots = append(snapshots, snapshot)
+	} else {
+		for _, source := range ConstructionSources() {
+			if snapshot, _, ok := constructionCache.Get(source.County()); ok {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+	}
+
+	now := time.Now()
+	cases := []ConstructionCase{}
+	for _, snapshot := range snapshots {
+		cases = append(cases, FilterConstructionCases(snapshot.Cases, query, now)...)
+	}
+
+	switch format := params.Get("format"); format {
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"cases":     cases,
+			"total":     len(cases),
+			"snapshots": exportSnapshotInfo(snapshots),
+		})
+	case "geojson":
+		w.Header().Set("Content-Type", "application/geo+json; charset=utf-8")
+		json.NewEncoder(w).Encode(constructionGeoJSON(cases))
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="construction.csv"`)
+		writeConstructionCSV(w, cases)
+	default:
+		http.Error(w, fmt.Sprintf("unsupported format %q", format), http.StatusBadRequest)
+	}
+}
+
+func exportSnapshotInfo(snapshots []ConstructionSnapshot) []map[string]interface{} {
+	info := []map[string]interface{}{}
+	for _, snapshot := range snapshots {
+		info = append(info, map[string]interface{}{
+			"county":    snapshot.County,
+			"updatedAt": snapshot.UpdatedAt,
+			"caseCount": len(snapshot.Cases),
+		})
+	}
+	return info
+}
+
+// constructionGeoJSON 組成 FeatureCollection，只使用已快取的地理編碼結果，沒有座標的案件 geometry 為 null。
+func constructionGeoJSON(cases []ConstructionCase) map[string]interface{} {
+	features := []map[string]interface{}{}
+	for _, c := range cases {
+		var geometry interface{}
+		if position, found, _ := geocodeCache.Lookup(caseGeocodeAddress(c)); found {
+			geometry = map[string]interface{}{
+				"type":        "Point",
+				"coordinates": []float64{position.Lng, position.Lat},
+			}
+		}
+		features = append(features, map[string]interface{}{
+			"type":     "Feature",
+			"id":       c.Key(),
+			"geometry": geometry,
+			"properties": map[string]interface{}{
+				"county":    c.County,
+				"caseId":    c.CaseID,
+				"agency":    c.Agency,
+				"reason":    c.Reason,
+				"location":  c.Location,
+				"start":     formatExportTime(c.Start),
+				"end":       formatExportTime(c.End),
+				"category":  c.Category,
+				"kind":      caseCategory(c),
+				"sourceUrl": c.SourceURL,
+			},
+		})
+	}
+	return map[string]interface{}{
+		"type":     "FeatureCollection",
+		"features": features,
+	}
+}
+
+func writeConstructionCSV(w http.ResponseWriter, cases []ConstructionCase) {
+	// 加上 BOM 讓 Excel 以 UTF-8 開啟
+	w.Write([]byte("\xef\xbb\xbf"))
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"county", "caseId", "agency", "reason", "location", "start", "end", "category", "kind", "sourceUrl", "lat", "lng"})
+	for _, c := range cases {
+		var lat, lng string
+		if position, found, _ := geocodeCache.Lookup(caseGeocodeAddress(c)); found {
+			lat = fmt.Sprintf("%f", position.Lat)
+			lng = fmt.Sprintf("%f", position.Lng)
+		}
+		writer.Write([]string{
+			c.County, c.CaseID, c.Agency, c.Reason, c.Location,
+			formatExportTime(c.Start), formatExportTime(c.End),
+			c.Category, string(caseCategory(c)), c.SourceURL, lat, lng,
+		})
+	}
+	writer.Flush()
+}
+
+// formatExportTime 以 RFC 3339 輸出台北時間，未知日期輸出空字串。
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(taipeiLocation).Format(time.RFC3339)
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConstructionExportEmptyCasesIsArray(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	constructionExportHandler(recorder, httptest.NewRequest("GET", "/api/construction?keyword=不存在的路", nil))
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %q: %v", recorder.Body.String(), err)
+	}
+	if got := string(body["cases"]); got != "[]" {
+		t.Errorf("cases = %s, want []", got)
+	}
+}
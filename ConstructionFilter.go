@@ -0,0 +1,296 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/text/width"
+)
+
+// ConstructionQuery 為一次道路施工查詢的條件。
+type ConstructionQuery struct {
+	County   string               // 縣市名稱
+	District string               // 行政區，例如 "大安區"，空字串表示不限
+	Keywords []string             // 道路或地點關鍵字，需全部符合
+	Window   string               // 日期條件，例如 "今天"、"本週"、"未來7天"、"12/25"
+	Category ConstructionCategory // 施工類別，空字串表示不限
+}
+
+// 行政區名稱的結尾，用來從關鍵字中辨識出行政區。縣轄市另外判斷，
+// 避免把 "士林夜市" 之類以市結尾的地標當成行政區
+var districtSuffixes = []string{"區", "鄉", "鎮"}
+
+// 段、巷、弄等門牌單位前的中文數字
+var addressNumeralPattern = regexp.MustCompile(`([零〇一二三四五六七八九十百]+)(段|巷|弄|號|樓)`)
+
+// ParseConstructionQuery 解析指令中縣市之後的參數，例如 ["台北市", "大安區 忠孝東路", "本週"]，
+// 第一個以區、鄉、鎮結尾的詞或三個字的縣轄市 (例如 "竹北市") 視為行政區，
+// 第一個日期條件視為查詢期間，第一個類別詞 (例如 "搶修") 視為施工類別。
+func ParseConstructionQuery(args []string) ConstructionQuery {
+	var query ConstructionQuery
+	if len(args) == 0 {
+		return query
+	}
+	query.County = strings.TrimSpace(args[0])
+
+	for _, arg := range args[1:] {
+		for _, word := range strings.Fields(width.Narrow.String(arg)) {
+			if query.Window == "" && isDateWindow(word) {
+				query.Window = word
+				continue
+			}
+			if query.Category == "" {
+				if category, ok := parseConstructionCategory(word); ok {
+					query.Category = category
+					continue
+				}
+			}
+			if query.District == "" && isDistrictName(word) {
+				query.District = word
+				continue
+			}
+			query.Keywords = append(query.Keywords, word)
+		}
+	}
+	return query
+}
+
+func isDistrictName(word string) bool {
+	// 至少兩個字 (例如台中市的 "中區")，單獨的 "區" 不算
+	runes := []rune(word)
+	if len(runes) < 2 {
+		return false
+	}
+	// 縣轄市都是三個字，例如 "竹北市"、"員林市"
+	if strings.HasSuffix(word, "市") {
+		return len(runes) == 3
+	}
+	for _, suffix := range districtSuffixes {
+		if strings.HasSuffix(word, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match 判斷案件是否符合類別，且地點符合行政區與所有關鍵字。
+func (q ConstructionQuery) Match(c ConstructionCase) bool {
+	if q.Category != "" && caseCategory(c) != q.Category {
+		return false
+	}
+	location := normalizeAddress(c.Location)
+	if q.District != "" {
+		district := normalizeAddress(q.District)
+		if !strings.Contains(location, district) {
+			// 地點常省略 "區" 等結尾，例如 "大安忠孝東路"
+			trimmed := []rune(district)
+			if len(trimmed) < 3 || !strings.Contains(location, string(trimmed[:len(trimmed)-1])) {
+				return false
+			}
+		}
+	}
+	for _, keyword := range q.Keywords {
+		if !strings.Contains(location, normalizeAddress(keyword)) {
+			return false
+		}
+	}
+	return true
+}
+
+// 施工查詢翻頁的 postback action 名稱
+const constructionPostbackAction = "construction"
+
+// LINE postback data 的長度上限
+const postbackDataLimit = 300
+
+// 查詢條件存放在伺服器上的時間，超過後翻頁按鈕會要求重新查詢
+const postbackQueryTTL = 24 * time.Hour
+
+// errNotConstructionPostback 表示 postback 不是施工查詢的翻頁按鈕
+var errNotConstructionPostback = errors.New("不是施工查詢的 postback")
+
+// errPostbackExpired 表示翻頁按鈕對應的查詢條件已不在伺服器上，例如已過期或服務重新啟動
+var errPostbackExpired = errors.New("施工查詢條件已過期")
+
+// PostbackData 將查詢條件與頁面起始位置編碼為 postback data。中文編碼後約為三倍長，
+// 超過 LINE 的長度上限時改將查詢條件存放在伺服器上，data 只帶代號與起始位置，
+// 避免捨去任何條件而讓下一頁變成另一個查詢。
+func (q ConstructionQuery) PostbackData(offset int) string {
+	values := url.Values{
+		"action": {constructionPostbackAction},
+		"county": {q.County},
+		"offset": {strconv.Itoa(offset)},
+	}
+	if q.District != "" {
+		values.Set("district", q.District)
+	}
+	if len(q.Keywords) > 0 {
+		values.Set("keywords", strings.Join(q.Keywords, " "))
+	}
+	if q.Window != "" {
+		values.Set("window", q.Window)
+	}
+	if q.Category != "" {
+		values.Set("category", string(q.Category))
+	}
+	if data := values.Encode(); len(data) <= postbackDataLimit {
+		return data
+	}
+
+	return url.Values{
+		"action": {constructionPostbackAction},
+		"token":  {postbackQueries.Put(q, time.Now())},
+		"offset": {strconv.Itoa(offset)},
+	}.Encode()
+}
+
+// ParseConstructionPostback 解析 PostbackData 產生的資料。不是施工查詢的 postback 時回傳
+// errNotConstructionPostback，伺服器上已沒有對應的查詢條件時回傳 errPostbackExpired。
+func ParseConstructionPostback(data string) (query ConstructionQuery, offset int, err error) {
+	values, err := url.ParseQuery(data)
+	if err != nil || values.Get("action") != constructionPostbackAction {
+		return query, 0, errNotConstructionPostback
+	}
+	offset, _ = strconv.Atoi(values.Get("offset"))
+	if token := values.Get("token"); token != "" {
+		query, ok := postbackQueries.Get(token, time.Now())
+		if !ok {
+			return query, 0, errPostbackExpired
+		}
+		return query, offset, nil
+	}
+
+	query.County = values.Get("county")
+	query.District = values.Get("district")
+	query.Keywords = strings.Fields(values.Get("keywords"))
+	query.Window = values.Get("window")
+	query.Category = ConstructionCategory(values.Get("category"))
+	return query, offset, nil
+}
+
+// postbackQueryStore 保存放不進 postback data 的查詢條件，以隨機代號取用。
+type postbackQueryStore struct {
+	mu      sync.Mutex
+	queries map[string]storedPostbackQuery
+}
+
+type storedPostbackQuery struct {
+	query   ConstructionQuery
+	expires time.Time
+}
+
+var postbackQueries = &postbackQueryStore{queries: map[string]storedPostbackQuery{}}
+
+// Put 保存查詢條件並回傳代號，同時清除已過期的條件。
+func (s *postbackQueryStore) Put(q ConstructionQuery, now time.Time) string {
+	b := make([]byte, 9)
+	rand.Read(b)
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for t, stored := range s.queries {
+		if now.After(stored.expires) {
+			delete(s.queries, t)
+		}
+	}
+	s.queries[token] = storedPostbackQuery{query: q, expires: now.Add(postbackQueryTTL)}
+	return token
+}
+
+// Get 依代號取回尚未過期的查詢條件。
+func (s *postbackQueryStore) Get(token string, now time.Time) (ConstructionQuery, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.queries[token]
+	if !ok || now.After(stored.expires) {
+		return ConstructionQuery{}, false
+	}
+	return stored.query, true
+}
+
+// Filtered 表示查詢是否帶有縣市以外的篩選條件。
+func (q ConstructionQuery) Filtered() bool {
+	return q.District != "" || len(q.Keywords) > 0 || q.Window != "" || q.Category != ""
+}
+
+// FilterConstructionCases 回傳符合查詢條件的案件。有日期條件時只保留與期間重疊的案件，
+// 並依對交通造成影響的先後排序；否則保留來源原本的順序。
+func FilterConstructionCases(cases []ConstructionCase, q ConstructionQuery, now time.Time) []ConstructionCase {
+	if !q.Filtered() {
+		return cases
+	}
+	from, to, hasWindow := parseDateWindow(q.Window, now)
+	var matched []ConstructionCase
+	for _, c := range cases {
+		if !q.Match(c) {
+			continue
+		}
+		if hasWindow && !c.Overlaps(from, to) {
+			continue
+		}
+		matched = append(matched, c)
+	}
+	if hasWindow {
+		if from.After(now) {
+			now = from
+		}
+		sortByImpact(matched, now)
+	}
+	return matched
+}
+
+// normalizeAddress 統一地址寫法以便比對：臺→台、全形轉半形、
+// 移除空白，並將段、巷、弄等單位前的中文數字轉為阿拉伯數字。
+func normalizeAddress(s string) string {
+	s = width.Narrow.String(s)
+	s = strings.ReplaceAll(s, "臺", "台")
+	s = strings.Join(strings.Fields(s), "")
+	return addressNumeralPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sub := addressNumeralPattern.FindStringSubmatch(m)
+		n, ok := parseChineseNumeral(sub[1])
+		if !ok {
+			return m
+		}
+		return strconv.Itoa(n) + sub[2]
+	})
+}
+
+// parseChineseNumeral 將 "四"、"十二"、"一百零五" 等 999 以內的中文數字轉為整數。
+func parseChineseNumeral(s string) (int, bool) {
+	digits := map[rune]int{
+		'零': 0, '〇': 0, '一': 1, '二': 2, '三': 3, '四': 4,
+		'五': 5, '六': 6, '七': 7, '八': 8, '九': 9,
+	}
+	total, current := 0, 0
+	for _, r := range s {
+		switch r {
+		case '百':
+			if current == 0 {
+				current = 1
+			}
+			total += current * 100
+			current = 0
+		case '十':
+			if current == 0 {
+				current = 1
+			}
+			total += current * 10
+			current = 0
+		default:
+			d, ok := digits[r]
+			if !ok {
+				return 0, false
+			}
+			current = d
+		}
+	}
+	return total + current, true
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConstructionQuery(t *testing.T) {
+	tests := []struct {
+		args []string
+		want ConstructionQuery
+	}{
+		{
+			args: []string{"台北市", "大安區 忠孝東路", "本週"},
+			want: ConstructionQuery{County: "台北市", District: "大安區", Keywords: []string{"忠孝東路"}, Window: "本週"},
+		},
+		{
+			args: []string{"台北市", "士林夜市"},
+			want: ConstructionQuery{County: "台北市", Keywords: []string{"士林夜市"}},
+		},
+		{
+			args: []string{"新竹縣", "竹北市 光明六路"},
+			want: ConstructionQuery{County: "新竹縣", District: "竹北市", Keywords: []string{"光明六路"}},
+		},
+		{
+			args: []string{"台中市", "中區 搶修"},
+			want: ConstructionQuery{County: "台中市", District: "中區", Category: CategoryEmergency},
+		},
+	}
+	for _, tt := range tests {
+		if got := ParseConstructionQuery(tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseConstructionQuery(%q) = %+v, want %+v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestConstructionPostbackRoundTrip(t *testing.T) {
+	query := ConstructionQuery{
+		County:   "台北市",
+		District: "大安區",
+		Keywords: []string{"a;b", "忠孝東路&復興南路", "100%"},
+		Window:   "未來7天",
+		Category: CategoryEmergency,
+	}
+	data := query.PostbackData(5)
+	if strings.Contains(data, "token=") {
+		t.Errorf("short query should be encoded inline: %s", data)
+	}
+	got, offset, err := ParseConstructionPostback(data)
+	if err != nil || offset != 5 || !reflect.DeepEqual(got, query) {
+		t.Errorf("round trip = %+v, %d, %v; want %+v, 5, nil", got, offset, err, query)
+	}
+}
+
+func TestConstructionPostbackLimit(t *testing.T) {
+	query := ConstructionQuery{County: "台北市", District: "大安區", Window: "本週"}
+	for i := 0; i < 20; i++ {
+		query.Keywords = append(query.Keywords, "忠孝東路四段")
+	}
+	data := query.PostbackData(5)
+	if len(data) > postbackDataLimit || !strings.Contains(data, "token=") {
+		t.Fatalf("postback data = %q (%d bytes), want a token within %d bytes", data, len(data), postbackDataLimit)
+	}
+	// 所有關鍵字都保留，下一頁與第一頁是同一個查詢
+	got, offset, err := ParseConstructionPostback(data)
+	if err != nil || offset != 5 || !reflect.DeepEqual(got, query) {
+		t.Errorf("parsed %+v, %d, %v; want %+v, 5, nil", got, offset, err, query)
+	}
+
+	if _, _, err := ParseConstructionPostback("action=construction&token=unknown&offset=5"); !errors.Is(err, errPostbackExpired) {
+		t.Errorf("unknown token: err = %v, want errPostbackExpired", err)
+	}
+	if _, _, err := ParseConstructionPostback("action=other"); !errors.Is(err, errNotConstructionPostback) {
+		t.Errorf("other action: err = %v, want errNotConstructionPostback", err)
+	}
+}
+
+func TestPostbackQueryStoreExpiry(t *testing.T) {
+	store := &postbackQueryStore{queries: map[string]storedPostbackQuery{}}
+	now := taipeiDate(2024, 12, 25, 9, 0)
+	query := ConstructionQuery{County: "台北市", Keywords: []string{"忠孝東路"}}
+	token := store.Put(query, now)
+
+	if got, ok := store.Get(token, now.Add(postbackQueryTTL)); !ok || !reflect.DeepEqual(got, query) {
+		t.Errorf("before expiry = %+v, %v", got, ok)
+	}
+	if _, ok := store.Get(token, now.Add(postbackQueryTTL+time.Second)); ok {
+		t.Error("expired query is still returned")
+	}
+	store.Put(query, now.Add(postbackQueryTTL+time.Second))
+	if _, ok := store.queries[token]; ok {
+		t.Error("expired query was not pruned")
+	}
+}
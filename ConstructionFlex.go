@@ -0,0 +1,284 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Flex Message 的 altText 最多 400 字
+const flexAltTextLimit = 400
+
+// constructionCarousel 將一頁施工結果組成 Flex carousel，每筆案件一個 bubble，
+// 還有下一頁時最後附上翻頁用的 bubble。
+func constructionCarousel(page ConstructionPage) map[string]interface{} {
+	var bubbles []map[string]interface{}
+	for _, c := range page.Cases {
+		bubbles = append(bubbles, constructionBubble(c, page))
+	}
+	if page.HasNext() {
+		bubbles = append(bubbles, constructionNextPageBubble(page))
+	}
+	return map[string]interface{}{
+		"type":     "carousel",
+		"contents": bubbles,
+	}
+}
+
+func constructionBubble(c ConstructionCase, page ConstructionPage) map[string]interface{} {
+	location := c.Location
+	if location == "" {
+		location = "無地點資料"
+	}
+	kind := caseCategory(c)
+	category := c.Category
+	if category == "" {
+		category = kind.Label()
+	}
+
+	body := []map[string]interface{}{
+		{
+			"type":  "text",
+			"text":  c.County,
+			"size":  "xs",
+			"color": "#888888",
+		},
+		{
+			"type":   "text",
+			"text":   location,
+			"size":   "md",
+			"weight": "bold",
+			"wrap":   true,
+			"margin": "md",
+		},
+		constructionBubbleRow("期間", c.Period()),
+	}
+	if c.Reason != "" {
+		body = append(body, constructionBubbleRow("原因", c.Reason))
+	}
+	if c.Agency != "" {
+		body = append(body, constructionBubbleRow("單位", c.Agency))
+	}
+	if c.CaseID != "" {
+		body = append(body, constructionBubbleRow("編號", c.CaseID))
+	}
+
+	note := formatUpdatedAt(page.UpdatedAt)
+	if page.Stale {
+		note = page.staleNotice() + "\n" + note
+	}
+	body = append(body, map[string]interface{}{
+		"type":   "text",
+		"text":   note,
+		"size":   "xxs",
+		"color":  "#AAAAAA",
+		"wrap":   true,
+		"margin": "lg",
+	})
+
+	return map[string]interface{}{
+		"type": "bubble",
+		"size": "kilo",
+		"header": map[string]interface{}{
+			"type":            "box",
+			"layout":          "vertical",
+			"backgroundColor": constructionCategoryColors[kind],
+			"paddingAll":      "8px",
+			"contents": []map[string]interface{}{
+				{
+					"type":   "text",
+					"text":   category,
+					"size":   "sm",
+					"color":  "#FFFFFF",
+					"weight": "bold",
+				},
+			},
+		},
+		"body": map[string]interface{}{
+			"type":     "box",
+			"layout":   "vertical",
+			"contents": body,
+		},
+		"footer": map[string]interface{}{
+			"type":   "box",
+			"layout": "vertical",
+			"contents": []map[string]interface{}{
+				{
+					"type":   "button",
+					"style":  "link",
+					"height": "sm",
+					"action": map[string]interface{}{
+						"type":  "uri",
+						"label": "在地圖上查看",
+						"uri":   "https://www.google.com/maps/search/?api=1&query=" + url.QueryEscape(caseGeocodeAddress(c)),
+					},
+				},
+			},
+		},
+	}
+}
+
+func constructionBubbleRow(label, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":    "box",
+		"layout":  "baseline",
+		"spacing": "sm",
+		"margin":  "sm",
+		"contents": []map[string]interface{}{
+			{
+				"type":  "text",
+				"text":  label,
+				"size":  "xs",
+				"color": "#AAAAAA",
+				"flex":  1,
+			},
+			{
+				"type":  "text",
+				"text":  value,
+				"size":  "xs",
+				"color": "#555555",
+				"wrap":  true,
+				"flex":  4,
+			},
+		},
+	}
+}
+
+func constructionNextPageBubble(page ConstructionPage) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "bubble",
+		"size": "kilo",
+		"body": map[string]interface{}{
+			"type":           "box",
+			"layout":         "vertical",
+			"justifyContent": "center",
+			"contents": []map[string]interface{}{
+				{
+					"type":  "text",
+					"text":  fmt.Sprintf("第 %d-%d 筆，共 %d 筆", page.Offset+1, page.NextOffset(), page.Total),
+					"size":  "sm",
+					"color": "#888888",
+					"align": "center",
+				},
+				{
+					"type":   "button",
+					"style":  "primary",
+					"color":  "#E67E22",
+					"margin": "lg",
+					"action": map[string]interface{}{
+						"type":        "postback",
+						"label":       "下一頁",
+						"data":        page.Query.PostbackData(page.NextOffset()),
+						"displayText": "下一頁",
+					},
+				},
+			},
+		},
+	}
+}
+
+// constructionTrendBubble 以長條圖的方式呈現施工統計的行政區與道路排行。
+func constructionTrendBubble(t ConstructionTrend) map[string]interface{} {
+	body := []map[string]interface{}{
+		{
+			"type":  "text",
+			"text":  fmt.Sprintf("%s 至 %s", formatROCDate(t.From), formatROCDate(t.To.AddDate(0, 0, -1))),
+			"size":  "xs",
+			"color": "#888888",
+		},
+		{
+			"type":   "text",
+			"text":   fmt.Sprintf("期間內施工案件 %d 件", t.Total),
+			"size":   "md",
+			"weight": "bold",
+			"margin": "md",
+		},
+	}
+	body = append(body, constructionTrendSection("行政區施工案件數", t.Districts)...)
+	body = append(body, constructionTrendSection("最常施工的道路", t.Roads)...)
+
+	return map[string]interface{}{
+		"type": "bubble",
+		"header": map[string]interface{}{
+			"type":            "box",
+			"layout":          "vertical",
+			"backgroundColor": "#E67E22",
+			"paddingAll":      "10px",
+			"contents": []map[string]interface{}{
+				{
+					"type":   "text",
+					"text":   fmt.Sprintf("%s施工統計 (%s)", t.County, t.Period),
+					"size":   "md",
+					"color":  "#FFFFFF",
+					"weight": "bold",
+				},
+			},
+		},
+		"body": map[string]interface{}{
+			"type":     "box",
+			"layout":   "vertical",
+			"contents": body,
+		},
+	}
+}
+
+func constructionTrendSection(title string, items []TrendItem) []map[string]interface{} {
+	if len(items) == 0 {
+		return nil
+	}
+	contents := []map[string]interface{}{
+		{"type": "separator", "margin": "lg"},
+		{
+			"type":   "text",
+			"text":   title,
+			"size":   "sm",
+			"weight": "bold",
+			"color":  "#555555",
+			"margin": "lg",
+		},
+	}
+	// 長條長度以第一名為 100%
+	top := items[0].Count
+	for _, item := range items {
+		contents = append(contents, map[string]interface{}{
+			"type":       "box",
+			"layout":     "horizontal",
+			"spacing":    "sm",
+			"margin":     "sm",
+			"alignItems": "center",
+			"contents": []map[string]interface{}{
+				{
+					"type":  "text",
+					"text":  item.Name,
+					"size":  "xs",
+					"color": "#555555",
+					"flex":  3,
+				},
+				{
+					"type":   "box",
+					"layout": "vertical",
+					"flex":   5,
+					"contents": []map[string]interface{}{
+						{
+							"type":            "box",
+							"layout":          "vertical",
+							"width":           fmt.Sprintf("%d%%", item.Count*100/top),
+							"height":          "8px",
+							"backgroundColor": "#E67E22",
+							"cornerRadius":    "4px",
+							"contents":        []map[string]interface{}{},
+						},
+					},
+				},
+				{
+					"type":  "text",
+					"text":  fmt.Sprintf("%d", item.Count),
+					"size":  "xs",
+					"color": "#888888",
+					"align": "end",
+					"flex":  1,
+				},
+			},
+		})
+	}
+	return contents
+}
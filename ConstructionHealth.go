@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// SchemaDriftError 表示爬取的網頁結構與預期不符，通常代表縣市網站已改版。
+type SchemaDriftError struct {
+	County string
+	Reason string
+}
+
+func (e *SchemaDriftError) Error() string {
+	return fmt.Sprintf("%s 網頁結構異常: %s", e.County, e.Reason)
+}
+
+// pageSchema 描述爬取的網頁中預期的表格結構。
+type pageSchema struct {
+	header     string   // 表頭列的選擇器，空字串表示不檢查表頭
+	columns    []string // 各欄表頭應包含的文字，依解析時讀取的欄位填寫，空字串表示不檢查該欄
+	rows       string   // 資料列的選擇器
+	minColumns int      // 每個資料列至少需要的 td 數，0 表示不檢查
+	skipRows   int      // 資料列中開頭不是案件的列數
+}
+
+// checkPageSchema 檢查網頁是否仍符合 schema：表頭文字與 columns 不符、找不到資料列或欄位數不足
+// 都會回傳 *SchemaDriftError。表頭仍符合但沒有資料列時視為當天沒有案件，不算改版。
+func checkPageSchema(county string, doc *goquery.Document, schema pageSchema) error {
+	drift := func(format string, args ...interface{}) error {
+		return &SchemaDriftError{County: county, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	if schema.header != "" {
+		header := doc.Find(schema.header).First()
+		if header.Length() == 0 {
+			return drift("找不到表頭 (%s)", schema.header)
+		}
+		var cells []string
+		header.Find("th, td").Each(func(i int, s *goquery.Selection) {
+			cells = append(cells, strings.Join(strings.Fields(s.Text()), ""))
+		})
+		for i, want := range schema.columns {
+			if want == "" {
+				continue
+			}
+			if i >= len(cells) || !strings.Contains(cells[i], want) {
+				return drift("第 %d 欄表頭應包含「%s」，實際表頭為 [%s]", i+1, want, strings.Join(cells, "|"))
+			}
+		}
+	}
+
+	rows := doc.Find(schema.rows)
+	if rows.Length() <= schema.skipRows {
+		if schema.header != "" {
+			return nil
+		}
+		return drift("沒有解析到任何資料列 (%s)", schema.rows)
+	}
+
+	if schema.minColumns > 0 {
+		var shortRow int
+		rows.Slice(schema.skipRows, rows.Length()).EachWithBreak(func(i int, s *goquery.Selection) bool {
+			if s.Find("td").Length() < schema.minColumns {
+				shortRow = schema.skipRows + i + 1
+				return false
+			}
+			return true
+		})
+		if shortRow > 0 {
+			return drift("第 %d 列欄位數少於 %d", shortRow, schema.minColumns)
+		}
+	}
+	return nil
+}
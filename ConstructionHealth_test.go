@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestCheckPageSchema(t *testing.T) {
+	schema := pageSchema{
+		header:     hsinchuCountyGrid + gridHeaderRow,
+		columns:    []string{"編號", "", "", "位置", "期間"},
+		rows:       hsinchuCountyGrid + gridDataRows,
+		minColumns: 5,
+	}
+	row := `<tr><td>H1</td><td>a</td><td>b</td><td>竹北市光明六路</td><td>113/12/01~113/12/15</td></tr>`
+	tests := []struct {
+		name  string
+		html  string
+		drift bool
+	}{
+		{"符合", `<table class="GridViewCss"><tr><th>案件編號</th><th>單位</th><th>類別</th><th>施工位置</th><th>施工期間</th></tr>` + row + `</table>`, false},
+		{"表頭改名", `<table class="GridViewCss"><tr><th>案件編號</th><th>單位</th><th>類別</th><th>施工日期</th><th>施工位置</th></tr>` + row + `</table>`, true},
+		{"沒有表格", `<div>系統維護中</div>`, true},
+		{"當天沒有案件", `<table class="GridViewCss"><tr><th>案件編號</th><th>單位</th><th>類別</th><th>施工位置</th><th>施工期間</th></tr></table>`, false},
+		{"欄位不足", `<table class="GridViewCss"><tr><th>案件編號</th><th>單位</th><th>類別</th><th>施工位置</th><th>施工期間</th></tr><tr><td>H1</td><td>a</td></tr></table>`, true},
+	}
+	for _, tt := range tests {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = checkPageSchema("新竹縣", doc, schema)
+		var drift *SchemaDriftError
+		if got := errors.As(err, &drift); got != tt.drift {
+			t.Errorf("%s: drift = %v (%v), want %v", tt.name, got, err, tt.drift)
+		}
+	}
+}
+
+func TestHualienWithoutCasesIsNotDrift(t *testing.T) {
+	page := `<html><body><form>
+<table id="ctl00_ContentPlaceHolder1_CList_ctl00_Table1">
+  <tr><td>施工單位</td><td>查詢條件</td><td>請選擇</td><td></td><td>請選擇請選擇</td></tr>
+</table>
+</form></body></html>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	cases, err := hualienSource{url: server.URL}.Fetch(context.Background())
+	if err != nil || len(cases) != 0 {
+		t.Errorf("Fetch = %d cases, %v; want no cases and no error", len(cases), err)
+	}
+}
+
+func TestHualienColumnReorderIsDrift(t *testing.T) {
+	tests := []struct {
+		name string
+		row  string
+	}{
+		{"日期與地點對調", `<tr><td>1</td><td>台灣自來水公司第九區管理處</td><td>花蓮市中山路100號</td><td>自來水</td><td>113/12/02~113/12/20</td></tr>`},
+		{"欄位減少", `<tr><td>1</td><td>台灣自來水公司第九區管理處</td><td>113/12/02~113/12/20</td></tr>`},
+	}
+	for _, tt := range tests {
+		page := `<html><body><form>
+<table id="ctl00_ContentPlaceHolder1_CList_ctl00_Table1">
+  <tr><td>施工單位</td><td>查詢條件</td><td>請選擇</td><td></td><td>請選擇請選擇</td></tr>
+</table>
+<table id="ctl00_ContentPlaceHolder1_CList_ctl01_Table1">` + tt.row + `</table>
+</form></body></html>`
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(page))
+		}))
+		_, err := hualienSource{url: server.URL}.Fetch(context.Background())
+		server.Close()
+		var drift *SchemaDriftError
+		if !errors.As(err, &drift) {
+			t.Errorf("%s: err = %v, want *SchemaDriftError", tt.name, err)
+		}
+	}
+}
+
+func TestGridWithoutRowsIsNotDrift(t *testing.T) {
+	page := `<html><body><form>
+<table class="GridViewCss"><tr><th>案件編號</th><th>申請單位</th><th>挖掘類別</th><th>施工位置</th><th>施工期間</th></tr></table>
+</form></body></html>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	cases, err := hsinchuCountySource{url: server.URL}.Fetch(context.Background())
+	if err != nil || len(cases) != 0 {
+		t.Errorf("Fetch = %d cases, %v; want no cases and no error", len(cases), err)
+	}
+}
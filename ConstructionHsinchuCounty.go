@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const hsinchuCountyConstructionURL = "https://pu.hsinchu.gov.tw/svc/svc/CaseList.aspx"
+
+// 案件列表的 GridView
+const hsinchuCountyGrid = "table.GridViewCss"
+
+type hsinchuCountySource struct {
+	url string
+}
+
+func init() {
+	RegisterConstructionSource(hsinchuCountySource{url: hsinchuCountyConstructionURL})
+}
+
+func (hsinchuCountySource) County() string    { return "新竹縣" }
+func (hsinchuCountySource) Aliases() []string { return nil }
+
+func (hsinchuCountySource) FetchTimeout() time.Duration { return webFormsFetchTimeout }
+
+func (source hsinchuCountySource) Fetch(ctx context.Context) ([]ConstructionCase, error) {
+	var cases []ConstructionCase
+	err := webFormsPages(ctx, source.County(), source.url, nil, func(page int, doc *goquery.Document) error {
+		if err := checkPageSchema(source.County(), doc, pageSchema{
+			header:     hsinchuCountyGrid + gridHeaderRow,
+			columns:    []string{"編號", "", "", "位置", "期間"},
+			rows:       hsinchuCountyGrid + gridDataRows,
+			minColumns: 5,
+		}); err != nil {
+			return err
+		}
+
+		doc.Find(hsinchuCountyGrid + gridDataRows).Each(func(i int, s *goquery.Selection) {
+			tds := s.Find("td")
+			caseNo := strings.TrimSpace(tds.Eq(0).Text())
+			location := strings.TrimSpace(tds.Eq(3).Text())
+			period := strings.TrimSpace(tds.Eq(4).Text())
+
+			start, end := parseCasePeriod(period)
+			cases = append(cases, ConstructionCase{
+				CaseID:    caseNo,
+				Location:  location,
+				Start:     start,
+				End:       end,
+				SourceURL: source.url,
+				Raw: map[string]string{
+					"案件編號": caseNo,
+					"施工位置": location,
+					"施工期間": period,
+				},
+			})
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uniqueCases(cases), nil
+}
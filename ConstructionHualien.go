@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const hualienConstructionURL = "https://pipe.hl.gov.tw/hualienpipe/Pub/PubQuery.aspx"
+
+// 每筆案件所在的表格
+const hualienCaseTables = "table[id^='ctl00_ContentPlaceHolder1_CList_ctl'][id$='Table1']"
+
+type hualienSource struct {
+	url string
+}
+
+func init() {
+	RegisterConstructionSource(hualienSource{url: hualienConstructionURL})
+}
+
+func (hualienSource) County() string    { return "花蓮縣" }
+func (hualienSource) Aliases() []string { return nil }
+
+func (hualienSource) FetchTimeout() time.Duration { return webFormsFetchTimeout }
+
+func (source hualienSource) Fetch(ctx context.Context) ([]ConstructionCase, error) {
+	var cases []ConstructionCase
+	err := webFormsPages(ctx, source.County(), source.url, nil, func(page int, doc *goquery.Document) error {
+		// 每筆案件各自是一個表格，沒有共同的表頭，改為逐一檢查案件表格的結構
+		if err := checkPageSchema(source.County(), doc, pageSchema{
+			rows: hualienCaseTables,
+		}); err != nil {
+			return err
+		}
+		var driftErr error
+		doc.Find(hualienCaseTables).EachWithBreak(func(i int, s *goquery.Selection) bool {
+			if isHualienQueryTable(s) {
+				return true
+			}
+			c, err := parseHualienCase(s, source.url)
+			if err != nil {
+				driftErr = &SchemaDriftError{County: source.County(), Reason: fmt.Sprintf("第 %d 個案件表格%v", i+1, err)}
+				return false
+			}
+			cases = append(cases, c)
+			return true
+		})
+		return driftErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uniqueCases(cases), nil
+}
+
+var htmlTagPattern = regexp.MustCompile(`\<.*?\>`)
+
+// isHualienQueryTable 判斷是否為與案件表格同樣命名的查詢條件表格，
+// 這類表格帶有下拉選單或尚未選擇的「請選擇」選項。
+func isHualienQueryTable(s *goquery.Selection) bool {
+	return s.Find("select, input").Length() > 0 || strings.Contains(s.Text(), "請選擇")
+}
+
+// parseHualienCase 解析單一案件表格，欄位數不足或施工日期無法解析時回傳錯誤，
+// 代表網站可能已調整欄位順序。
+func parseHualienCase(s *goquery.Selection, sourceURL string) (ConstructionCase, error) {
+	tds := s.Find("td")
+	if tds.Length() < 5 {
+		return ConstructionCase{}, fmt.Errorf("欄位數少於 5")
+	}
+
+	unit := strings.TrimSpace(tds.Eq(1).Text())
+	date := strings.TrimSpace(tds.Eq(2).Text())
+	start, end := parseCasePeriod(date)
+	if start.IsZero() && end.IsZero() {
+		return ConstructionCase{}, fmt.Errorf("的第 3 欄「%s」不是施工日期", date)
+	}
+
+	locationNode := tds.Eq(4)
+	locationHtml, err := locationNode.Html()
+	if err != nil {
+		locationHtml = locationNode.Text()
+	}
+	locationHtml = strings.ReplaceAll(locationHtml, "<br />", "\n")
+	locationHtml = strings.ReplaceAll(locationHtml, "<br/>", "\n")
+	locationHtml = strings.ReplaceAll(locationHtml, "<br>", "\n")
+	locationHtml = html.UnescapeString(locationHtml)
+
+	// 移除所有 HTML 標籤
+	locationText := strings.TrimSpace(htmlTagPattern.ReplaceAllString(locationHtml, ""))
+
+	// 提取施工地點與合約編號，忽略 "其他事項："
+	var location, contractNo string
+	for _, line := range strings.Split(locationText, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "合約編號：") {
+			contractNo = strings.TrimSpace(strings.TrimPrefix(line, "合約編號："))
+			continue
+		}
+		if location == "" && line != "" && !strings.HasPrefix(line, "其他事項：") {
+			location = line
+		}
+	}
+
+	return ConstructionCase{
+		CaseID:    contractNo,
+		Agency:    unit,
+		Location:  location,
+		Start:     start,
+		End:       end,
+		SourceURL: sourceURL,
+		Raw: map[string]string{
+			"施工單位": unit,
+			"施工日期": date,
+			"施工地點": locationText,
+		},
+	}, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+const kaohsiungConstructionURL = "https://pipegis.kcg.gov.tw/openDataService.aspx"
+
+type kaohsiungSource struct {
+	url string
+}
+
+func init() {
+	RegisterConstructionSource(kaohsiungSource{url: kaohsiungConstructionURL})
+}
+
+func (kaohsiungSource) County() string    { return "高雄市" }
+func (kaohsiungSource) Aliases() []string { return nil }
+
+func (s kaohsiungSource) Fetch(ctx context.Context) ([]ConstructionCase, error) {
+	body, err := fetchConstructionBody(ctx, s.County(), s.url)
+	if err != nil {
+		return nil, err
+	}
+
+	type DigCaseInfo struct {
+		Location string `xml:"LOCATION"`
+		DateDigS string `xml:"DATE_DIG_S"`
+		DateDigE string `xml:"DATE_DIG_E"`
+		Reason   string `xml:"REASON"`
+	}
+
+	var kaohsiungData struct {
+		XMLName     xml.Name      `xml:"DigCaseInfos"`
+		DigCaseInfo []DigCaseInfo `xml:"DigCaseInfo"`
+	}
+	if err := xml.Unmarshal(body, &kaohsiungData); err != nil {
+		return nil, fmt.Errorf("解析 XML 失敗: %w", err)
+	}
+
+	var cases []ConstructionCase
+	for _, item := range kaohsiungData.DigCaseInfo {
+		cases = append(cases, ConstructionCase{
+			Reason:    strings.TrimSpace(item.Reason),
+			Location:  strings.TrimSpace(item.Location),
+			Start:     parseCaseTime(item.DateDigS),
+			End:       parseCaseTime(item.DateDigE),
+			SourceURL: s.url,
+			Raw: map[string]string{
+				"LOCATION":   item.Location,
+				"DATE_DIG_S": item.DateDigS,
+				"DATE_DIG_E": item.DateDigE,
+				"REASON":     item.Reason,
+			},
+		})
+	}
+	return cases, nil
+}
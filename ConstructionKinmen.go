@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const kinmenConstructionURL = "https://roaddig.kinmen.gov.tw/KMDigAPI/api/OpenData/GetCaseList"
+
+type kinmenSource struct {
+	url string
+}
+
+func init() {
+	RegisterConstructionSource(kinmenSource{url: kinmenConstructionURL})
+}
+
+func (kinmenSource) County() string    { return "金門縣" }
+func (kinmenSource) Aliases() []string { return nil }
+
+func (s kinmenSource) Fetch(ctx context.Context) ([]ConstructionCase, error) {
+	now := time.Now()
+	sdate := now.AddDate(0, 0, -30).Format("2006-01-02")
+	edate := now.AddDate(0, 0, 30).Format("2006-01-02")
+	body, err := fetchConstructionBody(ctx, s.County(), fmt.Sprintf("%s?sdate=%s&edate=%s", s.url, sdate, edate))
+	if err != nil {
+		return nil, err
+	}
+
+	type PeriodCaseData struct {
+		EngUse     string `json:"EngUse"`
+		Road       string `json:"Road"`
+		SchedStart string `json:"SchedStart"`
+		SchedStop  string `json:"SchedStop"`
+	}
+
+	var kinmenData struct {
+		IsSuccessful bool             `json:"IsSuccessful"`
+		ErrorMessage *string          `json:"ErrorMessage"`
+		Data         []PeriodCaseData `json:"Data"`
+	}
+	if err := json.Unmarshal(body, &kinmenData); err != nil {
+		return nil, fmt.Errorf("解析 JSON 失敗: %w", err)
+	}
+
+	if !kinmenData.IsSuccessful {
+		if kinmenData.ErrorMessage != nil {
+			return nil, errors.New(*kinmenData.ErrorMessage)
+		}
+		return nil, errors.New("API 回傳失敗")
+	}
+
+	var cases []ConstructionCase
+	for _, item := range kinmenData.Data {
+		cases = append(cases, ConstructionCase{
+			Reason:    strings.TrimSpace(item.EngUse),
+			Location:  strings.TrimSpace(item.Road),
+			Start:     parseCaseTime(item.SchedStart),
+			End:       parseCaseTime(item.SchedStop),
+			SourceURL: s.url,
+			Raw: map[string]string{
+				"EngUse":     item.EngUse,
+				"Road":       item.Road,
+				"SchedStart": item.SchedStart,
+				"SchedStop":  item.SchedStop,
+			},
+		})
+	}
+	return cases, nil
+}
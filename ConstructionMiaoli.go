@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const miaoliConstructionURL = "https://miaoli-road.miaoli.gov.tw/NewMiaoliWeb/Common/CaseList_New.aspx"
+
+type miaoliSource struct {
+	url string
+}
+
+func init() {
+	RegisterConstructionSource(miaoliSource{url: miaoliConstructionURL})
+}
+
+func (miaoliSource) County() string    { return "苗栗縣" }
+func (miaoliSource) Aliases() []string { return nil }
+
+func (source miaoliSource) Fetch(ctx context.Context) ([]ConstructionCase, error) {
+	body, err := fetchConstructionBody(ctx, source.County(), source.url)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("解析 HTML 失敗: %w", err)
+	}
+	if err := checkPageSchema(source.County(), doc, pageSchema{
+		header:     "table.gview tr:first-child",
+		columns:    []string{"地點", "編號", "", "名稱"},
+		rows:       "table.gview tr",
+		minColumns: 6,
+		skipRows:   1,
+	}); err != nil {
+		return nil, err
+	}
+
+	var cases []ConstructionCase
+	doc.Find("table.gview tr").Each(func(i int, s *goquery.Selection) {
+		if i == 0 {
+			return
+		}
+
+		var caseNo, location, projectName, startDate, endDate string
+		s.Find("td").Each(func(j int, td *goquery.Selection) {
+			text := strings.TrimSpace(td.Text())
+			switch j {
+			case 0:
+				location = text
+			case 1:
+				caseNo = text
+			case 3:
+				projectName = text
+			case 4:
+				startDate = text
+			case 5:
+				endDate = text
+			}
+		})
+
+		cases = append(cases, ConstructionCase{
+			CaseID:    caseNo,
+			Reason:    projectName,
+			Location:  location,
+			Start:     parseCaseTime(startDate),
+			End:       parseCaseTime(endDate),
+			SourceURL: source.url,
+			Raw: map[string]string{
+				"案件編號": caseNo,
+				"施工地點": location,
+				"工程名稱": projectName,
+				"施工起日": startDate,
+				"施工迄日": endDate,
+			},
+		})
+	})
+	return cases, nil
+}
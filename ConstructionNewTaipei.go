@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const newTaipeiConstructionURL = "https://data.ntpc.gov.tw/api/datasets/96b6101b-c033-4834-8bd5-e312651db7a0/json?page=1&size=1000"
+
+type newTaipeiSource struct {
+	url string
+}
+
+func init() {
+	RegisterConstructionSource(newTaipeiSource{url: newTaipeiConstructionURL})
+}
+
+func (newTaipeiSource) County() string    { return "新北市" }
+func (newTaipeiSource) Aliases() []string { return nil }
+
+func (s newTaipeiSource) Fetch(ctx context.Context) ([]ConstructionCase, error) {
+	body, err := fetchConstructionBody(ctx, s.County(), s.url)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []struct {
+		CaseID       string `json:"CaseID"`
+		CaseName     string `json:"案件名稱"`
+		DigSite      string `json:"DigSite"`
+		CaseStart    string `json:"CaseStart"`
+		CaseEnd      string `json:"CaseEnd"`
+		StrAllowTime string `json:"StrAllowTime"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("解析 JSON 錯誤: %w", err)
+	}
+
+	var cases []ConstructionCase
+	for _, item := range data {
+		cases = append(cases, ConstructionCase{
+			CaseID:    item.CaseID,
+			Reason:    item.CaseName,
+			Location:  item.DigSite,
+			Start:     parseCaseTime(item.CaseStart),
+			End:       parseCaseTime(item.CaseEnd),
+			SourceURL: s.url,
+			Raw: map[string]string{
+				"CaseID":       item.CaseID,
+				"案件名稱":         item.CaseName,
+				"DigSite":      item.DigSite,
+				"CaseStart":    item.CaseStart,
+				"CaseEnd":      item.CaseEnd,
+				"StrAllowTime": item.StrAllowTime,
+			},
+		})
+	}
+	return cases, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+const pingtungConstructionURL = "https://cdn.odportal.tw/api/v1/resource/SfsCLK-2/61ae820f3a1469002467f7c3"
+
+type pingtungSource struct {
+	url string
+}
+
+func init() {
+	RegisterConstructionSource(pingtungSource{url: pingtungConstructionURL})
+}
+
+func (pingtungSource) County() string    { return "屏東縣" }
+func (pingtungSource) Aliases() []string { return nil }
+
+func (s pingtungSource) Fetch(ctx context.Context) ([]ConstructionCase, error) {
+	body, err := fetchConstructionBody(ctx, s.County(), s.url)
+	if err != nil {
+		return nil, err
+	}
+
+	type PingtungCase struct {
+		Applicant    string `xml:"申請單位"`
+		ApprovalUnit string `xml:"核准機關"`
+		Reason       string `xml:"施工原因"`
+		Location     string `xml:"挖掘地點"`
+		PermitNumber string `xml:"道路挖掘許可證字號"`
+		StartDate    string `xml:"核准施工起始日期"`
+		EndDate      string `xml:"核准施工終止日期"`
+	}
+
+	var pingtungData struct {
+		XMLName xml.Name       `xml:"屏東縣道路挖掘施工資訊"`
+		Cases   []PingtungCase `xml:"屏東縣道路挖掘施工案件"`
+	}
+	if err := xml.Unmarshal(body, &pingtungData); err != nil {
+		return nil, fmt.Errorf("解析 XML 失敗: %w", err)
+	}
+
+	var cases []ConstructionCase
+	for _, item := range pingtungData.Cases {
+		cases = append(cases, ConstructionCase{
+			CaseID:    strings.TrimSpace(item.PermitNumber),
+			Agency:    strings.TrimSpace(item.Applicant),
+			Reason:    strings.TrimSpace(item.Reason),
+			Location:  strings.TrimSpace(item.Location),
+			Start:     parseCaseTime(item.StartDate),
+			End:       parseCaseTime(item.EndDate),
+			SourceURL: s.url,
+			Raw: map[string]string{
+				"申請單位":      item.Applicant,
+				"核准機關":      item.ApprovalUnit,
+				"施工原因":      item.Reason,
+				"挖掘地點":      item.Location,
+				"道路挖掘許可證字號": item.PermitNumber,
+				"核准施工起始日期":  item.StartDate,
+				"核准施工終止日期":  item.EndDate,
+			},
+		})
+	}
+	return cases, nil
+}
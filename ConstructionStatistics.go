@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+const hsinchuCityStatisticsURL = "https://opendata.hccg.gov.tw/API/v3/Rest/OpenData/A5C2E3B25BE2E9C2?take=100&skip=0"
+
+// ConstructionStatistic 為各單位的挖掘統計值，與個別施工案件分開提供。
+type ConstructionStatistic struct {
+	Unit        string
+	Percentage  string
+	TotalLength string
+	TotalArea   string
+}
+
+func (s ConstructionStatistic) String() string {
+	summary := fmt.Sprintf("%s: 百分比 %s", s.Unit, s.Percentage)
+	if s.TotalLength == "0" {
+		summary += "，總長度太小無法統計"
+	} else {
+		summary += fmt.Sprintf("，總長度 %s", s.TotalLength)
+	}
+	if s.TotalArea == "0" {
+		summary += "，總面積太小無法統計"
+	} else {
+		summary += fmt.Sprintf("，總面積 %s", s.TotalArea)
+	}
+	return summary
+}
+
+// constructionStatisticsSources 為有提供統計資料集的縣市
+var constructionStatisticsSources = map[string]func(ctx context.Context) ([]ConstructionStatistic, error){
+	"新竹市": fetchHsinchuCityStatistics,
+}
+
+// GetConstructionStatistics 回傳縣市的施工統計文字。
+func GetConstructionStatistics(county string) string {
+	fetch, ok := constructionStatisticsSources[county]
+	if !ok {
+		return fmt.Sprintf("%s目前沒有提供施工統計資料", county)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constructionFetchTimeout)
+	defer cancel()
+	statistics, err := fetch(ctx)
+	if err != nil {
+		log.Printf("取得施工統計失敗: %v", err)
+		return "Error，請再試一次"
+	}
+	if len(statistics) == 0 {
+		return fmt.Sprintf("%s目前沒有施工統計資料", county)
+	}
+
+	lines := []string{county + "各單位施工統計"}
+	for _, s := range statistics {
+		lines = append(lines, s.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func fetchHsinchuCityStatistics(ctx context.Context) ([]ConstructionStatistic, error) {
+	body, err := upstream.Get(ctx, "新竹市", hsinchuCityStatisticsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []struct {
+		UnitName    string `json:"單位名稱"`
+		Percentage  string `json:"百分比"`
+		TotalLength string `json:"總長度"`
+		TotalArea   string `json:"總面積"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("解析 JSON 錯誤: %w", err)
+	}
+
+	statistics := make([]ConstructionStatistic, 0, len(data))
+	for _, item := range data {
+		statistics = append(statistics, ConstructionStatistic{
+			Unit:        item.UnitName,
+			Percentage:  item.Percentage,
+			TotalLength: item.TotalLength,
+			TotalArea:   item.TotalArea,
+		})
+	}
+	return statistics, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const taichungConstructionURL = "https://datacenter.taichung.gov.tw/swagger/OpenData/d5adb71a-00bb-4573-b67e-ffdccfc7cd27"
+
+type taichungSource struct {
+	url string
+}
+
+func init() {
+	RegisterConstructionSource(taichungSource{url: taichungConstructionURL})
+}
+
+func (taichungSource) County() string    { return "台中市" }
+func (taichungSource) Aliases() []string { return []string{"臺中市"} }
+
+func (s taichungSource) Fetch(ctx context.Context) ([]ConstructionCase, error) {
+	body, err := fetchConstructionBody(ctx, s.County(), s.url)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []struct {
+		CaseNo      string `json:"申請書編號"`
+		Location    string `json:"地點"`
+		ProjectName string `json:"工程名稱"`
+		StartDate   string `json:"核准起日"`
+		EndDate     string `json:"核准迄日"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("解析 JSON 失敗: %w", err)
+	}
+
+	var cases []ConstructionCase
+	for _, c := range data {
+		cases = append(cases, ConstructionCase{
+			CaseID:    c.CaseNo,
+			Reason:    c.ProjectName,
+			Location:  c.Location,
+			Start:     parseCaseTime(c.StartDate),
+			End:       parseCaseTime(c.EndDate),
+			SourceURL: s.url,
+			Raw: map[string]string{
+				"申請書編號": c.CaseNo,
+				"地點":    c.Location,
+				"工程名稱":  c.ProjectName,
+				"核准起日":  c.StartDate,
+				"核准迄日":  c.EndDate,
+			},
+		})
+	}
+	return cases, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const taipeiConstructionURL = "https://tpnco.blob.core.windows.net/blobfs/Todaywork.json"
+
+type taipeiSource struct {
+	url string
+}
+
+func init() {
+	RegisterConstructionSource(taipeiSource{url: taipeiConstructionURL})
+}
+
+func (taipeiSource) County() string    { return "台北市" }
+func (taipeiSource) Aliases() []string { return []string{"臺北市"} }
+
+func (s taipeiSource) Fetch(ctx context.Context) ([]ConstructionCase, error) {
+	body, err := fetchConstructionBody(ctx, s.County(), s.url)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Features []struct {
+			Properties struct {
+				Ac_no   string `json:"Ac_no"`
+				AppTime string `json:"AppTime"`
+				Addr    string `json:"Addr"`
+				AppMode string `json:"AppMode"`
+			} `json:"properties"`
+		} `json:"features"`
+	}
+	body = bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")) // 移除 BOM
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("解析 JSON 錯誤: %w", err)
+	}
+
+	modeMap := map[string]string{
+		"0": "施工通報",
+		"3": "銑鋪通報",
+		"4": "搶修通報",
+		"5": "道路維護通報",
+		"6": "人手孔施工通報",
+		"B": "建案公設復舊",
+	}
+	kindMap := map[string]ConstructionCategory{
+		"0": CategoryPlanned,
+		"3": CategoryResurfacing,
+		"4": CategoryEmergency,
+		"5": CategoryResurfacing,
+		"6": CategoryManhole,
+		"B": CategoryRestoration,
+	}
+
+	var cases []ConstructionCase
+	for _, feature := range data.Features {
+		p := feature.Properties
+		modeName, ok := modeMap[p.AppMode]
+		if !ok {
+			modeName = "未知類別"
+		}
+		start, end := parseCasePeriod(p.AppTime)
+		cases = append(cases, ConstructionCase{
+			CaseID:    p.Ac_no,
+			Location:  p.Addr,
+			Start:     start,
+			End:       end,
+			Category:  modeName,
+			Kind:      kindMap[p.AppMode],
+			SourceURL: s.url,
+			Raw: map[string]string{
+				"Ac_no":   p.Ac_no,
+				"AppTime": p.AppTime,
+				"Addr":    p.Addr,
+				"AppMode": p.AppMode,
+			},
+		})
+	}
+	return cases, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const taoyuanConstructionURL = "http://data.tycg.gov.tw/api/v1/rest/datastore/52de3762-1490-4a86-a074-0062d746873b?format=json"
+
+type taoyuanSource struct {
+	url string
+}
+
+func init() {
+	RegisterConstructionSource(taoyuanSource{url: taoyuanConstructionURL})
+}
+
+func (taoyuanSource) County() string    { return "桃園市" }
+func (taoyuanSource) Aliases() []string { return nil }
+
+func (s taoyuanSource) Fetch(ctx context.Context) ([]ConstructionCase, error) {
+	body, err := fetchConstructionBody(ctx, s.County(), s.url)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+		Result  struct {
+			Records []struct {
+				CaseID    string `json:"CaseID"`
+				Start     string `json:"Start"`
+				Stop      string `json:"stop"`
+				SLocation string `json:"SLocation"`
+			} `json:"records"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("解析 JSON 錯誤: %w", err)
+	}
+
+	var cases []ConstructionCase
+	for _, item := range result.Result.Records {
+		cases = append(cases, ConstructionCase{
+			CaseID:    item.CaseID,
+			Location:  item.SLocation,
+			Start:     parseCaseTime(item.Start),
+			End:       parseCaseTime(item.Stop),
+			SourceURL: s.url,
+			Raw: map[string]string{
+				"CaseID":    item.CaseID,
+				"Start":     item.Start,
+				"stop":      item.Stop,
+				"SLocation": item.SLocation,
+			},
+		})
+	}
+	return cases, nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 最多翻到的頁數，避免網站異常時無限翻頁
+const webFormsMaxPages = 50
+
+// 需要翻頁的來源抓取完整資料的時間上限
+const webFormsFetchTimeout = 2 * time.Minute
+
+// GridView 的表頭列與資料列。分頁列內含表格，表頭為第一個非分頁列 (通常由 th 組成)
+const (
+	gridHeaderRow = " > tbody > tr:not(:has(table))"
+	gridDataRows  = " > tbody > tr:not(:first-child):not(:has(th)):not(:has(table))"
+)
+
+var (
+	// javascript:__doPostBack('ctl00$ContentPlaceHolder1$GridView1','Page$2')
+	doPostBackPattern = regexp.MustCompile(`__doPostBack\(\s*'([^']*)'\s*,\s*'([^']*)'\s*\)`)
+	// WebForm_DoPostBackWithOptions(new WebForm_PostBackOptions("ctl00$...$lbtnNext", "", true, "", "", false, true))
+	postBackOptionsPattern = regexp.MustCompile(`WebForm_PostBackOptions\(\s*"([^"]*)"\s*,\s*"([^"]*)"`)
+)
+
+// 沒有 Page$N 參數的自訂分頁按鈕文字
+var webFormsNextLabels = []string{"下一頁", "下頁", "Next", ">"}
+
+// webFormsPages 取得 ASP.NET WebForms 頁面，並重送帶有 __VIEWSTATE 與 __EVENTVALIDATION 的
+// postback 逐頁翻到最後一頁，每一頁都交給 visit 處理。
+func webFormsPages(ctx context.Context, county, pageURL string, header http.Header, visit func(page int, doc *goquery.Document) error) error {
+	body, err := upstream.Get(ctx, county, pageURL, header)
+	if err != nil {
+		return err
+	}
+
+	// 以頁面內容判斷是否回到已讀過的頁面。Page$Next 與自訂的下一頁按鈕每頁的
+	// postback 都相同，最後一頁的按鈕沒有停用時網站會重複回傳同一頁
+	visited := map[[sha1.Size]byte]bool{}
+	for page := 1; ; page++ {
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("解析第 %d 頁 HTML 失敗: %w", page, err)
+		}
+		content := sha1.Sum([]byte(doc.Find("body").Text()))
+		if visited[content] {
+			return nil
+		}
+		visited[content] = true
+		if err := visit(page, doc); err != nil {
+			return err
+		}
+
+		target, argument, ok := webFormsNextPage(doc, page)
+		if !ok {
+			return nil
+		}
+		if page >= webFormsMaxPages {
+			log.Printf("%s 超過 %d 頁，停止翻頁", county, webFormsMaxPages)
+			return nil
+		}
+
+		action, form := webFormsPostback(doc, pageURL, target, argument)
+		body, err = upstream.PostForm(ctx, county, action, form, header)
+		if err != nil {
+			return fmt.Errorf("讀取第 %d 頁失敗: %w", page+1, err)
+		}
+	}
+}
+
+// webFormsNextPage 找出前往下一頁的 postback 目標與參數。依序接受 GridView 的 Page$N、
+// Page$Next，以及文字為「下一頁」等的自訂按鈕；停用的按鈕沒有 href，不會被選到。
+func webFormsNextPage(doc *goquery.Document, page int) (target, argument string, ok bool) {
+	type postback struct{ target, argument, label string }
+	var postbacks []postback
+	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		m := doPostBackPattern.FindStringSubmatch(href)
+		if m == nil {
+			m = postBackOptionsPattern.FindStringSubmatch(href)
+		}
+		if m != nil {
+			postbacks = append(postbacks, postback{m[1], m[2], strings.TrimSpace(s.Text())})
+		}
+	})
+
+	next := fmt.Sprintf("Page$%d", page+1)
+	for _, want := range []string{next, "Page$Next"} {
+		for _, p := range postbacks {
+			if p.argument == want {
+				return p.target, p.argument, true
+			}
+		}
+	}
+	for _, p := range postbacks {
+		for _, label := range webFormsNextLabels {
+			if p.label == label {
+				return p.target, p.argument, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// webFormsPostback 以瀏覽器送出表單的方式組出 postback 的網址與欄位，
+// 包含 __VIEWSTATE、__EVENTVALIDATION 等隱藏欄位與目前的查詢條件。
+func webFormsPostback(doc *goquery.Document, pageURL, target, argument string) (string, url.Values) {
+	form := doc.Find("form").First()
+	values := url.Values{}
+
+	form.Find("input[name]").Each(func(i int, s *goquery.Selection) {
+		name, _ := s.Attr("name")
+		value, _ := s.Attr("value")
+		switch strings.ToLower(s.AttrOr("type", "text")) {
+		case "submit", "button", "image", "reset", "file":
+			// 只有被按下的按鈕才會送出
+		case "checkbox", "radio":
+			if _, checked := s.Attr("checked"); checked {
+				if value == "" {
+					value = "on"
+				}
+				values.Add(name, value)
+			}
+		default:
+			values.Add(name, value)
+		}
+	})
+	form.Find("select[name]").Each(func(i int, s *goquery.Selection) {
+		name, _ := s.Attr("name")
+		option := s.Find("option[selected]").First()
+		if option.Length() == 0 {
+			option = s.Find("option").First()
+		}
+		if option.Length() > 0 {
+			values.Add(name, option.AttrOr("value", option.Text()))
+		}
+	})
+	form.Find("textarea[name]").Each(func(i int, s *goquery.Selection) {
+		name, _ := s.Attr("name")
+		values.Add(name, s.Text())
+	})
+	values.Set("__EVENTTARGET", target)
+	values.Set("__EVENTARGUMENT", argument)
+
+	action := pageURL
+	if base, err := url.Parse(pageURL); err == nil {
+		if ref, err := url.Parse(form.AttrOr("action", "")); err == nil {
+			action = base.ResolveReference(ref).String()
+		}
+	}
+	return action, values
+}
+
+// uniqueCases 移除重複的案件，翻頁期間資料異動時同一案件可能出現在相鄰兩頁。
+func uniqueCases(cases []ConstructionCase) []ConstructionCase {
+	seen := map[string]bool{}
+	unique := cases[:0]
+	for _, c := range cases {
+		if seen[c.Key()] {
+			continue
+		}
+		seen[c.Key()] = true
+		unique = append(unique, c)
+	}
+	return unique
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// webFormsTestPages 為假 WebForms 網站的總頁數
+const webFormsTestPages = 3
+
+// webFormsTestPage 組出第 page 頁，pager 產生分頁列的連結。
+func webFormsTestPage(page int, pager func(page int) string) string {
+	return fmt.Sprintf(`<html><body>
+<form method="post" action="./list.aspx?mode=query" id="aspnetForm">
+<input type="hidden" name="__EVENTTARGET" id="__EVENTTARGET" value="" />
+<input type="hidden" name="__EVENTARGUMENT" id="__EVENTARGUMENT" value="" />
+<input type="hidden" name="__VIEWSTATE" id="__VIEWSTATE" value="vs%[1]d" />
+<input type="hidden" name="__EVENTVALIDATION" id="__EVENTVALIDATION" value="ev%[1]d" />
+<select name="ctl00$ddlTown"><option value="">全部</option><option value="302" selected="selected">竹北市</option></select>
+<input type="submit" name="ctl00$btnQuery" value="查詢" />
+<table class="GridViewCss">
+  <tr><th>案件編號</th></tr>
+  <tr><td class="case">case-%[1]d-a</td></tr>
+  <tr><td class="case">case-%[1]d-b</td></tr>
+  <tr><td colspan="1"><table><tr>%[2]s</tr></table></td></tr>
+</table>
+</form></body></html>`, page, pager(page))
+}
+
+// newWebFormsTestServer 啟動假 WebForms 網站，檢查每次翻頁的 postback 是否帶著目前頁面的
+// __VIEWSTATE、__EVENTVALIDATION、查詢條件，以及 argument 回傳的 __EVENTARGUMENT。
+func newWebFormsTestServer(t *testing.T, pager func(page int) string, argument func(page int) string) (*httptest.Server, *[]string) {
+	var posted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/list.aspx" {
+			t.Errorf("request path = %s", r.URL.Path)
+		}
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, webFormsTestPage(1, pager))
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		current, err := strconv.Atoi(strings.TrimPrefix(r.PostForm.Get("__VIEWSTATE"), "vs"))
+		if err != nil {
+			t.Errorf("__VIEWSTATE = %q", r.PostForm.Get("__VIEWSTATE"))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		posted = append(posted, r.PostForm.Get("__EVENTARGUMENT"))
+		if got := r.PostForm.Get("__EVENTVALIDATION"); got != fmt.Sprintf("ev%d", current) {
+			t.Errorf("page %d: __EVENTVALIDATION = %q", current, got)
+		}
+		if got := r.PostForm.Get("__EVENTTARGET"); got != "ctl00$GridView1" {
+			t.Errorf("page %d: __EVENTTARGET = %q", current, got)
+		}
+		if got, want := r.PostForm.Get("__EVENTARGUMENT"), argument(current); got != want {
+			t.Errorf("page %d: __EVENTARGUMENT = %q, want %q", current, got, want)
+		}
+		if got := r.PostForm.Get("ctl00$ddlTown"); got != "302" {
+			t.Errorf("page %d: ddlTown = %q, want the selected option", current, got)
+		}
+		if r.PostForm.Has("ctl00$btnQuery") {
+			t.Errorf("page %d: the query button should not be posted", current)
+		}
+		// 最後一頁的下一頁按鈕沒有停用時，網站會再回傳一次最後一頁
+		next := current + 1
+		if next > webFormsTestPages {
+			next = webFormsTestPages
+		}
+		fmt.Fprint(w, webFormsTestPage(next, pager))
+	}))
+	t.Cleanup(server.Close)
+	return server, &posted
+}
+
+func TestWebFormsPages(t *testing.T) {
+	tests := []struct {
+		name     string
+		pager    func(page int) string
+		argument func(page int) string
+		posts    int
+	}{
+		{
+			name: "Page$N",
+			pager: func(page int) string {
+				var links string
+				for n := 1; n <= webFormsTestPages; n++ {
+					if n == page {
+						links += fmt.Sprintf("<td><span>%d</span></td>", n)
+						continue
+					}
+					links += fmt.Sprintf(`<td><a href="javascript:__doPostBack('ctl00$GridView1','Page$%d')">%d</a></td>`, n, n)
+				}
+				return links
+			},
+			argument: func(page int) string { return fmt.Sprintf("Page$%d", page+1) },
+			posts:    webFormsTestPages - 1,
+		},
+		{
+			// Page$Next 每頁的 postback 都相同，最後一頁的按鈕也沒有停用
+			name: "Page$Next",
+			pager: func(page int) string {
+				return `<td><a href="javascript:__doPostBack('ctl00$GridView1','Page$First')">第一頁</a></td>` +
+					`<td><a href="javascript:__doPostBack('ctl00$GridView1','Page$Next')">下一頁</a></td>`
+			},
+			argument: func(page int) string { return "Page$Next" },
+			posts:    webFormsTestPages,
+		},
+		{
+			// 自訂的下一頁按鈕，最後一頁停用 (沒有 href)
+			name: "custom next button",
+			pager: func(page int) string {
+				if page == webFormsTestPages {
+					return `<td><a disabled="disabled">下一頁</a></td>`
+				}
+				return `<td><a href="javascript:WebForm_DoPostBackWithOptions(new WebForm_PostBackOptions(&quot;ctl00$GridView1&quot;, &quot;&quot;, true, &quot;&quot;, &quot;&quot;, false, true))">下一頁</a></td>`
+			},
+			argument: func(page int) string { return "" },
+			posts:    webFormsTestPages - 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, posted := newWebFormsTestServer(t, tt.pager, tt.argument)
+			var cases []string
+			var pages []int
+			err := webFormsPages(context.Background(), "新竹縣", server.URL+"/list.aspx", nil, func(page int, doc *goquery.Document) error {
+				pages = append(pages, page)
+				doc.Find("td.case").Each(func(i int, s *goquery.Selection) {
+					cases = append(cases, s.Text())
+				})
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pages) != webFormsTestPages {
+				t.Errorf("visited pages %v, want %d pages", pages, webFormsTestPages)
+			}
+			want := "case-1-a,case-1-b,case-2-a,case-2-b,case-3-a,case-3-b"
+			if got := strings.Join(cases, ","); got != want {
+				t.Errorf("cases = %s, want %s", got, want)
+			}
+			if len(*posted) != tt.posts {
+				t.Errorf("postbacks = %q, want %d", *posted, tt.posts)
+			}
+		})
+	}
+}
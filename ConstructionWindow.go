@@ -0,0 +1,110 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// 省略年份的日期，例如 "12/25"
+var monthDayPattern = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})$`)
+
+// isDateWindow 判斷查詢參數是否為日期條件：今天、本週、未來7天或特定日期。
+func isDateWindow(word string) bool {
+	_, _, ok := parseDateWindow(word, time.Now())
+	return ok
+}
+
+// parseDateWindow 將日期條件轉為 [from, to) 區間，皆為台北時間的午夜。
+func parseDateWindow(word string, now time.Time) (from, to time.Time, ok bool) {
+	now = now.In(taipeiLocation)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, taipeiLocation)
+
+	switch word {
+	case "今天", "今日":
+		return today, today.AddDate(0, 0, 1), true
+	case "明天", "明日":
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 2), true
+	case "本週", "本周", "這週", "這周":
+		// 週一為一週的開始
+		offset := (int(today.Weekday()) + 6) % 7
+		monday := today.AddDate(0, 0, -offset)
+		return monday, monday.AddDate(0, 0, 7), true
+	case "未來7天", "未來七天", "近7天", "一週內":
+		return today, today.AddDate(0, 0, 7), true
+	}
+
+	if m := monthDayPattern.FindStringSubmatch(word); m != nil {
+		month, _ := strconv.Atoi(m[1])
+		day, _ := strconv.Atoi(m[2])
+		date := time.Date(today.Year(), time.Month(month), day, 0, 0, 0, 0, taipeiLocation)
+		if int(date.Month()) != month || date.Day() != day {
+			return time.Time{}, time.Time{}, false
+		}
+		return date, date.AddDate(0, 0, 1), true
+	}
+
+	// 只接受完整日期，避免把門牌號碼等數字當成日期
+	if len(word) >= 6 {
+		if date, err := ParseTaiwanDate(word); err == nil {
+			date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, taipeiLocation)
+			return date, date.AddDate(0, 0, 1), true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// Overlaps 判斷案件期間是否與 [from, to) 重疊，只有日期的迄日視為當天整天。
+// 起迄日都未知的案件無法判斷，視為不重疊。
+func (c ConstructionCase) Overlaps(from, to time.Time) bool {
+	if c.Start.IsZero() && c.End.IsZero() {
+		return false
+	}
+	if !c.Start.IsZero() && !c.Start.Before(to) {
+		return false
+	}
+	if c.End.IsZero() {
+		return true
+	}
+	return c.effectiveEnd().After(from)
+}
+
+// effectiveEnd 回傳施工實際結束的時間，只有日期的迄日延到隔天午夜。
+func (c ConstructionCase) effectiveEnd() time.Time {
+	end := c.End
+	if end.Hour() == 0 && end.Minute() == 0 && end.Second() == 0 {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+// sortByImpact 依對交通造成影響的先後排序：施工中的案件在前並依迄日排序，
+// 尚未開始的依起日排序，期間未知的排在最後。
+func sortByImpact(cases []ConstructionCase, now time.Time) {
+	impact := func(c ConstructionCase) (time.Time, time.Time, bool) {
+		if c.Start.IsZero() && c.End.IsZero() {
+			return time.Time{}, time.Time{}, false
+		}
+		start := c.Start
+		if start.IsZero() || start.Before(now) {
+			start = now
+		}
+		end := c.End
+		if end.IsZero() {
+			end = start
+		}
+		return start, end, true
+	}
+	sort.SliceStable(cases, func(i, j int) bool {
+		si, ei, oki := impact(cases[i])
+		sj, ej, okj := impact(cases[j])
+		if oki != okj {
+			return oki
+		}
+		if !si.Equal(sj) {
+			return si.Before(sj)
+		}
+		return ei.Before(ej)
+	})
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateWindow(t *testing.T) {
+	wednesday := taipeiDate(2024, 12, 25, 15, 30)
+	tests := []struct {
+		word     string
+		now      time.Time
+		from, to time.Time
+		ok       bool
+	}{
+		{"今天", wednesday, taipeiDate(2024, 12, 25, 0, 0), taipeiDate(2024, 12, 26, 0, 0), true},
+		{"明天", wednesday, taipeiDate(2024, 12, 26, 0, 0), taipeiDate(2024, 12, 27, 0, 0), true},
+		// 一週從週一開始，週一與週日都屬於同一週
+		{"本週", wednesday, taipeiDate(2024, 12, 23, 0, 0), taipeiDate(2024, 12, 30, 0, 0), true},
+		{"本周", taipeiDate(2024, 12, 23, 0, 0), taipeiDate(2024, 12, 23, 0, 0), taipeiDate(2024, 12, 30, 0, 0), true},
+		{"這週", taipeiDate(2024, 12, 29, 23, 59), taipeiDate(2024, 12, 23, 0, 0), taipeiDate(2024, 12, 30, 0, 0), true},
+		// UTC 仍是週日，台北已是週一
+		{"本週", time.Date(2024, 12, 29, 17, 0, 0, 0, time.UTC), taipeiDate(2024, 12, 30, 0, 0), taipeiDate(2025, 1, 6, 0, 0), true},
+		{"未來7天", wednesday, taipeiDate(2024, 12, 25, 0, 0), taipeiDate(2025, 1, 1, 0, 0), true},
+		{"12/31", wednesday, taipeiDate(2024, 12, 31, 0, 0), taipeiDate(2025, 1, 1, 0, 0), true},
+		{"113/12/31", wednesday, taipeiDate(2024, 12, 31, 0, 0), taipeiDate(2025, 1, 1, 0, 0), true},
+		{"2/30", wednesday, time.Time{}, time.Time{}, false},
+		{"181", wednesday, time.Time{}, time.Time{}, false},
+		{"忠孝東路", wednesday, time.Time{}, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		from, to, ok := parseDateWindow(tt.word, tt.now)
+		if ok != tt.ok || !from.Equal(tt.from) || !to.Equal(tt.to) {
+			t.Errorf("parseDateWindow(%q, %v) = %v, %v, %v; want %v, %v, %v",
+				tt.word, tt.now, from, to, ok, tt.from, tt.to, tt.ok)
+		}
+	}
+}
+
+func TestConstructionCaseOverlaps(t *testing.T) {
+	from, to := taipeiDate(2024, 12, 25, 0, 0), taipeiDate(2024, 12, 26, 0, 0)
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       bool
+	}{
+		// 只有日期的迄日視為當天整天
+		{"date-only end on the day", taipeiDate(2024, 12, 20, 0, 0), taipeiDate(2024, 12, 25, 0, 0), true},
+		{"date-only end the day before", taipeiDate(2024, 12, 20, 0, 0), taipeiDate(2024, 12, 24, 0, 0), false},
+		{"timed end at noon", taipeiDate(2024, 12, 20, 0, 0), taipeiDate(2024, 12, 25, 12, 0), true},
+		{"timed end the evening before", taipeiDate(2024, 12, 20, 0, 0), taipeiDate(2024, 12, 24, 22, 0), false},
+		{"starts at the window end", taipeiDate(2024, 12, 26, 0, 0), taipeiDate(2024, 12, 28, 0, 0), false},
+		{"starts within the window", taipeiDate(2024, 12, 25, 23, 0), taipeiDate(2024, 12, 28, 0, 0), true},
+		{"no end date", taipeiDate(2024, 12, 1, 0, 0), time.Time{}, true},
+		{"no start date", time.Time{}, taipeiDate(2024, 12, 25, 0, 0), true},
+		{"unknown period", time.Time{}, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		c := ConstructionCase{Start: tt.start, End: tt.end}
+		if got := c.Overlaps(from, to); got != tt.want {
+			t.Errorf("%s: Overlaps = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSortByImpact(t *testing.T) {
+	now := taipeiDate(2024, 12, 25, 9, 0)
+	cases := []ConstructionCase{
+		{CaseID: "unknown"},
+		{CaseID: "next week", Start: taipeiDate(2024, 12, 30, 0, 0), End: taipeiDate(2025, 1, 5, 0, 0)},
+		{CaseID: "active, ends later", Start: taipeiDate(2024, 12, 1, 0, 0), End: taipeiDate(2024, 12, 31, 0, 0)},
+		{CaseID: "tomorrow", Start: taipeiDate(2024, 12, 26, 0, 0), End: taipeiDate(2024, 12, 27, 0, 0)},
+		{CaseID: "active, ends soon", Start: taipeiDate(2024, 12, 20, 0, 0), End: taipeiDate(2024, 12, 25, 17, 0)},
+		{CaseID: "active, no start", End: taipeiDate(2024, 12, 28, 0, 0)},
+	}
+	sortByImpact(cases, now)
+
+	want := []string{"active, ends soon", "active, no start", "active, ends later", "tomorrow", "next week", "unknown"}
+	for i, c := range cases {
+		if c.CaseID != want[i] {
+			t.Errorf("position %d = %q, want %q", i, c.CaseID, want[i])
+		}
+	}
+}
+
+func TestFilterConstructionCasesByWindow(t *testing.T) {
+	now := taipeiDate(2024, 12, 25, 9, 0)
+	cases := []ConstructionCase{
+		{CaseID: "last week", Location: "大安區忠孝東路", Start: taipeiDate(2024, 12, 16, 0, 0), End: taipeiDate(2024, 12, 20, 0, 0)},
+		{CaseID: "sunday", Location: "大安區忠孝東路", Start: taipeiDate(2024, 12, 29, 0, 0), End: taipeiDate(2024, 12, 29, 0, 0)},
+		{CaseID: "today", Location: "大安區忠孝東路", Start: taipeiDate(2024, 12, 25, 9, 0), End: taipeiDate(2024, 12, 25, 17, 0)},
+		{CaseID: "next monday", Location: "大安區忠孝東路", Start: taipeiDate(2024, 12, 30, 0, 0), End: taipeiDate(2024, 12, 31, 0, 0)},
+	}
+	got := FilterConstructionCases(cases, ConstructionQuery{County: "台北市", Window: "本週"}, now)
+	if len(got) != 2 || got[0].CaseID != "today" || got[1].CaseID != "sunday" {
+		t.Errorf("本週 = %+v", got)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+)
+
+const yilanConstructionURL = "https://cdn.odportal.tw/api/v1/resource/DSNTMGUM/61b504bb6e97860024674b09"
+
+type yilanSource struct {
+	url string
+}
+
+func init() {
+	RegisterConstructionSource(yilanSource{url: yilanConstructionURL})
+}
+
+func (yilanSource) County() string    { return "宜蘭縣" }
+func (yilanSource) Aliases() []string { return nil }
+
+func (s yilanSource) Fetch(ctx context.Context) ([]ConstructionCase, error) {
+	body, err := fetchConstructionBody(ctx, s.County(), s.url)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseDigCaseXML(body, s.url)
+}
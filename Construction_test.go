@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// serveFixture 啟動回傳 testdata 中回應內容的本機伺服器，不論路徑與查詢參數都回傳同一份內容。
+func serveFixture(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func taipeiDate(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, taipeiLocation)
+}
+
+func TestConstructionSourceFixtures(t *testing.T) {
+	tests := []struct {
+		fixture string
+		source  func(url string) ConstructionSource
+		count   int
+		first   ConstructionCase
+	}{
+		{
+			fixture: "taipei.json",
+			source:  func(url string) ConstructionSource { return taipeiSource{url: url} },
+			count:   2,
+			first: ConstructionCase{
+				County: "台北市", CaseID: "11312250001", Location: "大安區忠孝東路四段181號前",
+				Start: taipeiDate(2024, 12, 25, 9, 0), End: taipeiDate(2024, 12, 31, 17, 0),
+				Category: "搶修通報", Kind: CategoryEmergency,
+			},
+		},
+		{
+			fixture: "new_taipei.json",
+			source:  func(url string) ConstructionSource { return newTaipeiSource{url: url} },
+			count:   2,
+			first: ConstructionCase{
+				County: "新北市", CaseID: "1131200123", Reason: "自來水管線汰換工程", Location: "板橋區文化路一段188巷",
+				Start: taipeiDate(2024, 12, 1, 0, 0), End: taipeiDate(2024, 12, 31, 0, 0),
+			},
+		},
+		{
+			fixture: "taoyuan.json",
+			source:  func(url string) ConstructionSource { return taoyuanSource{url: url} },
+			count:   2,
+			first: ConstructionCase{
+				County: "桃園市", CaseID: "TY1131201001", Location: "桃園區中正路100號",
+				Start: taipeiDate(2024, 12, 1, 9, 0), End: taipeiDate(2024, 12, 20, 17, 0),
+			},
+		},
+		{
+			fixture: "taichung.json",
+			source:  func(url string) ConstructionSource { return taichungSource{url: url} },
+			count:   2,
+			first: ConstructionCase{
+				County: "台中市", CaseID: "1131201-0001", Reason: "台中市西屯區污水下水道工程", Location: "西屯區台灣大道三段99號",
+				Start: taipeiDate(2024, 12, 1, 0, 0), End: taipeiDate(2024, 12, 31, 0, 0),
+			},
+		},
+		{
+			fixture: "kaohsiung.xml",
+			source:  func(url string) ConstructionSource { return kaohsiungSource{url: url} },
+			count:   2,
+			first: ConstructionCase{
+				County: "高雄市", Reason: "自來水管線汰換", Location: "苓雅區四維三路2號",
+				Start: taipeiDate(2024, 12, 2, 0, 0), End: taipeiDate(2024, 12, 15, 0, 0),
+			},
+		},
+		{
+			fixture: "pingtung.xml",
+			source:  func(url string) ConstructionSource { return pingtungSource{url: url} },
+			count:   1,
+			first: ConstructionCase{
+				County: "屏東縣", CaseID: "屏府工字第1131201001號", Agency: "台灣電力股份有限公司屏東區營業處",
+				Reason: "電力管線汰換", Location: "屏東市自由路527號",
+				Start: taipeiDate(2024, 12, 1, 0, 0), End: taipeiDate(2024, 12, 20, 0, 0),
+			},
+		},
+		{
+			fixture: "dig_case.xml",
+			source:  func(url string) ConstructionSource { return yilanSource{url: url} },
+			count:   2,
+			first: ConstructionCase{
+				County: "宜蘭縣", CaseID: "1131201003", Agency: "中華電信股份有限公司", Reason: "電信管線維修",
+				Location: "宜蘭市中山路三段145號",
+				Start:    taipeiDate(2024, 12, 3, 0, 0), End: taipeiDate(2024, 12, 18, 0, 0),
+			},
+		},
+		{
+			fixture: "dig_case.xml",
+			source:  func(url string) ConstructionSource { return chiayiCitySource{url: url} },
+			count:   2,
+			first: ConstructionCase{
+				County: "嘉義市", CaseID: "1131201003", Agency: "中華電信股份有限公司", Reason: "電信管線維修",
+				Location: "宜蘭市中山路三段145號",
+				Start:    taipeiDate(2024, 12, 3, 0, 0), End: taipeiDate(2024, 12, 18, 0, 0),
+			},
+		},
+		{
+			fixture: "kinmen.json",
+			source:  func(url string) ConstructionSource { return kinmenSource{url: url} },
+			count:   2,
+			first: ConstructionCase{
+				County: "金門縣", Reason: "自來水管線工程", Location: "金城鎮民生路",
+				Start: taipeiDate(2024, 12, 2, 0, 0), End: taipeiDate(2024, 12, 13, 0, 0),
+			},
+		},
+		{
+			fixture: "miaoli.html",
+			source:  func(url string) ConstructionSource { return miaoliSource{url: url} },
+			count:   2,
+			first: ConstructionCase{
+				County: "苗栗縣", CaseID: "M1131201001", Reason: "電力管線汰換工程", Location: "苗栗市中正路1291號",
+				Start: taipeiDate(2024, 12, 2, 0, 0), End: taipeiDate(2024, 12, 20, 0, 0),
+			},
+		},
+		{
+			fixture: "hsinchu_county.html",
+			source:  func(url string) ConstructionSource { return hsinchuCountySource{url: url} },
+			count:   2,
+			first: ConstructionCase{
+				County: "新竹縣", CaseID: "H1131201001", Location: "竹北市光明六路10號",
+				Start: taipeiDate(2024, 12, 1, 0, 0), End: taipeiDate(2024, 12, 15, 0, 0),
+			},
+		},
+		{
+			fixture: "chiayi_county.html",
+			source:  func(url string) ConstructionSource { return chiayiCountySource{url: url} },
+			count:   2,
+			first: ConstructionCase{
+				County: "嘉義縣", Location: "起點：太保市祥和一路東段1號 終點：太保市祥和一路東段20號",
+				Start: taipeiDate(2024, 12, 2, 0, 0), End: taipeiDate(2024, 12, 18, 0, 0),
+			},
+		},
+		{
+			fixture: "hualien.html",
+			source:  func(url string) ConstructionSource { return hualienSource{url: url} },
+			count:   2,
+			first: ConstructionCase{
+				County: "花蓮縣", CaseID: "HL1131201001", Agency: "台灣自來水公司第九區管理處", Location: "花蓮市中山路100號",
+				Start: taipeiDate(2024, 12, 2, 0, 0), End: taipeiDate(2024, 12, 20, 0, 0),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		server := serveFixture(t, tt.fixture)
+		source := tt.source(server.URL)
+		t.Run(source.County(), func(t *testing.T) {
+			cases, err := fetchConstructionCases(context.Background(), source)
+			if err != nil {
+				t.Fatalf("fetch: %v", err)
+			}
+			if len(cases) != tt.count {
+				t.Fatalf("got %d cases, want %d", len(cases), tt.count)
+			}
+			got, want := cases[0], tt.first
+			if got.County != want.County || got.CaseID != want.CaseID || got.Agency != want.Agency ||
+				got.Reason != want.Reason || got.Location != want.Location || got.Category != want.Category {
+				t.Errorf("first case = %+v, want %+v", got, want)
+			}
+			if !got.Start.Equal(want.Start) || !got.End.Equal(want.End) {
+				t.Errorf("period = %v ~ %v, want %v ~ %v", got.Start, got.End, want.Start, want.End)
+			}
+			if want.Kind != "" && got.Kind != want.Kind {
+				t.Errorf("kind = %q, want %q", got.Kind, want.Kind)
+			}
+			if got.SourceURL == "" || len(got.Raw) == 0 {
+				t.Errorf("source URL or raw fields missing: %+v", got)
+			}
+		})
+	}
+}
+
+func TestHsinchuCityPointsToStatistics(t *testing.T) {
+	_, err := QueryConstruction(ConstructionQuery{County: "竹市"}, 0)
+	var noFeed *NoPublicFeedError
+	if !errors.As(err, &noFeed) {
+		t.Fatalf("err = %v, want *NoPublicFeedError", err)
+	}
+	want := "新竹市目前沒有公開的道路施工資料，暫時無法查詢\n可輸入「施工統計」與「新竹市」查看各單位的道路挖掘統計"
+	if got := constructionErrorMessage(err); got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+
+	if _, err := QueryConstruction(ConstructionQuery{County: "台南市"}, 0); !errors.Is(err, errUnsupportedCounty) {
+		t.Errorf("台南市: err = %v, want errUnsupportedCounty", err)
+	}
+}
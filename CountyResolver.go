@@ -0,0 +1,158 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// countyInfo 為縣市的標準名稱與常見的其他寫法 (簡稱、英文、漢語拼音)。
+type countyInfo struct {
+	Name    string
+	Aliases []string
+}
+
+// taiwanCounties 為全台 22 個縣市，不帶 "市"、"縣" 的寫法會自動加入別名。
+var taiwanCounties = []countyInfo{
+	{"基隆市", []string{"基市", "Keelung", "Keelung City", "Jilong"}},
+	{"台北市", []string{"北市", "Taipei", "Taipei City", "Taibei"}},
+	{"新北市", []string{"新北", "New Taipei", "New Taipei City", "Xinbei"}},
+	{"桃園市", []string{"桃市", "Taoyuan", "Taoyuan City"}},
+	{"新竹市", []string{"竹市", "Hsinchu City", "Xinzhu City", "Hsinchu", "Xinzhu"}},
+	{"新竹縣", []string{"竹縣", "Hsinchu County", "Xinzhu County", "Hsinchu", "Xinzhu"}},
+	{"苗栗縣", []string{"苗縣", "Miaoli", "Miaoli County"}},
+	{"台中市", []string{"中市", "Taichung", "Taichung City", "Taizhong"}},
+	{"彰化縣", []string{"彰縣", "Changhua", "Changhua County", "Zhanghua"}},
+	{"南投縣", []string{"投縣", "Nantou", "Nantou County"}},
+	{"雲林縣", []string{"雲縣", "Yunlin", "Yunlin County"}},
+	{"嘉義市", []string{"嘉市", "Chiayi City", "Jiayi City", "Chiayi", "Jiayi"}},
+	{"嘉義縣", []string{"嘉縣", "Chiayi County", "Jiayi County", "Chiayi", "Jiayi"}},
+	{"台南市", []string{"南市", "Tainan", "Tainan City"}},
+	{"高雄市", []string{"高市", "Kaohsiung", "Kaohsiung City", "Gaoxiong"}},
+	{"屏東縣", []string{"屏縣", "Pingtung", "Pingtung County", "Pingdong"}},
+	{"宜蘭縣", []string{"宜縣", "Yilan", "Yilan County", "Ilan"}},
+	{"花蓮縣", []string{"花縣", "Hualien", "Hualien County", "Hualian"}},
+	{"台東縣", []string{"東縣", "Taitung", "Taitung County", "Taidong"}},
+	{"澎湖縣", []string{"澎縣", "Penghu", "Penghu County"}},
+	{"金門縣", []string{"金門", "Kinmen", "Kinmen County", "Jinmen"}},
+	{"連江縣", []string{"馬祖", "Matsu", "Lienchiang", "Lienchiang County", "Lianjiang"}},
+}
+
+// countyAliasIndex 將正規化後的寫法對應到一或多個標準縣市名稱
+var countyAliasIndex = buildCountyAliasIndex()
+
+func buildCountyAliasIndex() map[string][]string {
+	index := map[string][]string{}
+	add := func(alias, name string) {
+		key := normalizeCountyInput(alias)
+		for _, existing := range index[key] {
+			if existing == name {
+				return
+			}
+		}
+		index[key] = append(index[key], name)
+	}
+	for _, county := range taiwanCounties {
+		add(county.Name, county.Name)
+		add(strings.TrimRight(county.Name, "市縣"), county.Name)
+		for _, alias := range county.Aliases {
+			add(alias, county.Name)
+		}
+	}
+	return index
+}
+
+// normalizeCountyInput 統一臺/台、全形半形、大小寫與空白。
+func normalizeCountyInput(s string) string {
+	s = strings.ToLower(normalizeAddress(s))
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r == '.' || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// ResolveCounty 將使用者輸入的縣市轉為標準名稱。
+// 只有一個結果時即為該縣市；多個結果表示輸入有歧義 (例如 "新竹")；
+// 沒有完全相符時會以編輯距離找出最接近的縣市，例如 "台北巿" 或 "Kaohsuing"。
+func ResolveCounty(input string) []string {
+	key := normalizeCountyInput(input)
+	if key == "" {
+		return nil
+	}
+	if names, ok := countyAliasIndex[key]; ok {
+		return names
+	}
+	// 資料來源自行註冊的別名
+	if county, ok := constructionAliases[strings.TrimSpace(input)]; ok {
+		return []string{county}
+	}
+
+	// 中文允許錯一個字，英文較長時允許錯兩個字母
+	maxDistance := 1
+	if isASCII(key) && len(key) >= 6 {
+		maxDistance = 2
+	}
+	best := maxDistance + 1
+	var matches []string
+	seen := map[string]bool{}
+	aliases := make([]string, 0, len(countyAliasIndex))
+	for alias := range countyAliasIndex {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		// 兩個字的簡稱只差一個字就會對到太多縣市，不納入模糊比對
+		if len([]rune(alias)) <= 2 {
+			continue
+		}
+		distance := levenshtein(key, alias)
+		if distance > best {
+			continue
+		}
+		if distance < best {
+			best = distance
+			matches = nil
+			seen = map[string]bool{}
+		}
+		for _, name := range countyAliasIndex[alias] {
+			if !seen[name] {
+				seen[name] = true
+				matches = append(matches, name)
+			}
+		}
+	}
+	return matches
+}
+
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// levenshtein 計算兩字串以 rune 為單位的編輯距離。
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 未設定 DIRECTIONS_BASE_URL 時使用的 Google Directions API 網址
+const defaultDirectionsBaseURL = "https://maps.googleapis.com/maps/api/directions/json"
+
+// DirectionsErrorKind 區分 Directions 查詢失敗的原因。
+type DirectionsErrorKind int
+
+const (
+	DirectionsNetworkError   DirectionsErrorKind = iota // 無法連線
+	DirectionsTimeout                                   // 等待回應逾時
+	DirectionsServerError                               // 服務回應 5xx 或 429
+	DirectionsBadResponse                               // 回應不是預期的 JSON
+	DirectionsNoRoute                                   // 此交通模式沒有可行的路線 (ZERO_RESULTS)
+	DirectionsNotFound                                  // 起點或終點無法定位 (NOT_FOUND)
+	DirectionsInvalidRequest                            // 請求內容不被接受，例如路線過長
+	DirectionsDenied                                    // API key 無效或超過配額，需由管理員處理
+)
+
+// DirectionsError 表示 Directions 查詢失敗，呼叫端依 Kind 回覆使用者不同的訊息。
+type DirectionsError struct {
+	Kind         DirectionsErrorKind
+	Status       string   // Directions API 回應的 status
+	ErrorMessage string   // Directions API 回應的 error_message
+	Mode         string   // 查詢的交通模式
+	NotFound     []string // 無法定位的地點，例如 "起點"
+	Err          error
+}
+
+func (e *DirectionsError) Error() string {
+	if e.Err != nil {
+		return "Directions: " + e.Err.Error()
+	}
+	message := "Directions status " + e.Status
+	if e.ErrorMessage != "" {
+		message += ": " + e.ErrorMessage
+	}
+	return message
+}
+
+// Temporary 表示錯誤可能在稍後重試時消失，例如逾時或服務暫時錯誤。
+func (e *DirectionsError) Temporary() bool {
+	switch e.Kind {
+	case DirectionsNetworkError, DirectionsTimeout, DirectionsServerError, DirectionsBadResponse:
+		return true
+	}
+	return false
+}
+
+func (e *DirectionsError) Unwrap() error {
+	return e.Err
+}
+
+// directionsErrorMessage 將查詢錯誤轉為回覆給使用者的訊息。
+func directionsErrorMessage(err error) string {
+	var directionsErr *DirectionsError
+	if !errors.As(err, &directionsErr) {
+		return "Error，請再試一次"
+	}
+	switch directionsErr.Kind {
+	case DirectionsTimeout:
+		return "Google Maps 回應逾時，請稍後再試"
+	case DirectionsServerError:
+		return "Google Maps 服務暫時無法使用，請稍後再試"
+	case DirectionsBadResponse:
+		return "無法解析 Google Maps 的回應，請稍後再試"
+	case DirectionsNoRoute:
+		return fmt.Sprintf("找不到%s可以抵達的路線，請改用其他交通模式或確認起點和終點", directionsModeLabel(directionsErr.Mode))
+	case DirectionsNotFound:
+		if len(directionsErr.NotFound) == 0 {
+			return "找不到起點或終點，請輸入更完整的地址或地標名稱"
+		}
+		return fmt.Sprintf("找不到%s，請輸入更完整的地址或地標名稱", strings.Join(directionsErr.NotFound, "和"))
+	case DirectionsInvalidRequest:
+		if directionsErr.Status == "MAX_ROUTE_LENGTH_EXCEEDED" {
+			return "路線過長，無法提供路線資訊，請縮短起點與終點的距離"
+		}
+		return "查詢條件有誤，請確認起點和終點"
+	case DirectionsDenied:
+		return "路線服務暫時無法使用，已通知管理員，請稍後再試"
+	}
+	return "無法連線到 Google Maps，請稍後再試"
+}
+
+// directionsModeLabels 為交通模式的中文名稱
+var directionsModeLabels = map[string]string{
+	"driving":   "開車",
+	"walking":   "走路",
+	"transit":   "大眾運輸",
+	"bicycling": "自行車",
+}
+
+func directionsModeLabel(mode string) string {
+	if label, ok := directionsModeLabels[mode]; ok {
+		return label
+	}
+	return directionsModeLabels["driving"]
+}
+
+// checkDirectionsStatus 依回應的 status 與 geocoded_waypoints 判斷查詢結果，OK 時回傳 nil。
+// API key 或配額問題會通知管理員。
+func checkDirectionsStatus(result DirectionsResponse, mode string) error {
+	newError := func(kind DirectionsErrorKind) *DirectionsError {
+		return &DirectionsError{Kind: kind, Status: result.Status, ErrorMessage: result.ErrorMessage, Mode: mode}
+	}
+
+	switch result.Status {
+	case "OK":
+		if _, ok := result.FirstLeg(); !ok {
+			return newError(DirectionsNoRoute)
+		}
+		return nil
+	case "ZERO_RESULTS":
+		return newError(DirectionsNoRoute)
+	case "NOT_FOUND":
+		err := newError(DirectionsNotFound)
+		for i, waypoint := range result.GeocodedWaypoints {
+			if waypoint.GeocoderStatus == "OK" {
+				continue
+			}
+			switch i {
+			case 0:
+				err.NotFound = append(err.NotFound, "起點")
+			case len(result.GeocodedWaypoints) - 1:
+				err.NotFound = append(err.NotFound, "終點")
+			default:
+				err.NotFound = append(err.NotFound, fmt.Sprintf("第 %d 個中途點", i))
+			}
+		}
+		return err
+	case "INVALID_REQUEST", "MAX_WAYPOINTS_EXCEEDED", "MAX_ROUTE_LENGTH_EXCEEDED":
+		return newError(DirectionsInvalidRequest)
+	case "REQUEST_DENIED", "OVER_QUERY_LIMIT", "OVER_DAILY_LIMIT":
+		err := newError(DirectionsDenied)
+		directionsAlerts.Record(err)
+		return err
+	case "UNKNOWN_ERROR":
+		return newError(DirectionsServerError)
+	}
+	return newError(DirectionsBadResponse)
+}
+
+// newUpstreamDirectionsError 依 upstream 的錯誤判斷是逾時、服務錯誤或連線失敗。
+func newUpstreamDirectionsError(err error) *DirectionsError {
+	var statusErr *UpstreamStatusError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return &DirectionsError{Kind: DirectionsTimeout, Err: err}
+	case errors.As(err, &statusErr) && (statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests):
+		return &DirectionsError{Kind: DirectionsServerError, Err: err}
+	case errors.Is(err, errResponseTooLarge), statusErr != nil:
+		return &DirectionsError{Kind: DirectionsBadResponse, Err: err}
+	}
+	return &DirectionsError{Kind: DirectionsNetworkError, Err: err}
+}
+
+// DirectionsRequest 為一次路線查詢的條件。
+type DirectionsRequest struct {
+	Origin        string
+	Destination   string
+	Mode          string    // driving、walking、transit、bicycling，空字串為 driving
+	DepartureTime time.Time // 出發時間，零值表示現在
+}
+
+// DirectionsProvider 提供路線查詢，即時路況、最佳路徑與預測高峰時段都透過它取得路線。
+type DirectionsProvider interface {
+	Directions(ctx context.Context, req DirectionsRequest) (DirectionsResponse, error)
+}
+
+// DirectionsResponse 為 Directions API 的回應。
+type DirectionsResponse struct {
+	Status            string             `json:"status"`
+	ErrorMessage      string             `json:"error_message,omitempty"`
+	GeocodedWaypoints []GeocodedWaypoint `json:"geocoded_waypoints"`
+	Routes            []DirectionsRoute  `json:"routes"`
+}
+
+// GeocodedWaypoint 為起點、終點的地理編碼結果，順序與請求中的地點相同。
+type GeocodedWaypoint struct {
+	GeocoderStatus string   `json:"geocoder_status"`
+	PlaceID        string   `json:"place_id"`
+	PartialMatch   bool     `json:"partial_match,omitempty"`
+	Types          []string `json:"types"`
+}
+
+// DirectionsRoute 為一條建議路線。
+type DirectionsRoute struct {
+	Summary          string          `json:"summary"`
+	Legs             []DirectionsLeg `json:"legs"`
+	Warnings         []string        `json:"warnings"`
+	Copyrights       string          `json:"copyrights"`
+	OverviewPolyline struct {
+		Points string `json:"points"`
+	} `json:"overview_polyline"`
+}
+
+// DirectionsLeg 為路線中兩個地點之間的一段。
+type DirectionsLeg struct {
+	StartAddress      string           `json:"start_address"`
+	EndAddress        string           `json:"end_address"`
+	StartLocation     LatLng           `json:"start_location"`
+	EndLocation       LatLng           `json:"end_location"`
+	Distance          TextValue        `json:"distance"`
+	Duration          TextValue        `json:"duration"`
+	DurationInTraffic *TextValue       `json:"duration_in_traffic,omitempty"` // 只有開車且指定出發時間時才有
+	Steps             []DirectionsStep `json:"steps"`
+}
+
+// DirectionsStep 為一段路線中的單一步驟。
+type DirectionsStep struct {
+	HTMLInstructions string    `json:"html_instructions"`
+	TravelMode       string    `json:"travel_mode"`
+	Maneuver         string    `json:"maneuver,omitempty"`
+	Distance         TextValue `json:"distance"`
+	Duration         TextValue `json:"duration"`
+	StartLocation    LatLng    `json:"start_location"`
+	EndLocation      LatLng    `json:"end_location"`
+}
+
+// TextValue 為距離或時間，Value 的單位為公尺或秒，Text 為依語言格式化的文字。
+type TextValue struct {
+	Text  string `json:"text"`
+	Value int    `json:"value"`
+}
+
+// FirstLeg 回傳第一條路線的第一段，沒有路線時回傳 false。
+func (r DirectionsResponse) FirstLeg() (DirectionsLeg, bool) {
+	if len(r.Routes) == 0 || len(r.Routes[0].Legs) == 0 {
+		return DirectionsLeg{}, false
+	}
+	return r.Routes[0].Legs[0], true
+}
+
+// googleDirections 透過 HTTP 呼叫 Directions API，baseURL 可指向本機的假伺服器。
+type googleDirections struct {
+	baseURL string
+	apiKey  string
+}
+
+var directionsProvider DirectionsProvider = newGoogleDirections(os.Getenv("DIRECTIONS_BASE_URL"), os.Getenv("GOOGLE_MAPS_API_KEY"))
+
+// newGoogleDirections 建立 Directions API 的 provider，baseURL 為空字串時使用 Google 的網址。
+func newGoogleDirections(baseURL, apiKey string) *googleDirections {
+	if baseURL == "" {
+		baseURL = defaultDirectionsBaseURL
+	}
+	return &googleDirections{baseURL: baseURL, apiKey: apiKey}
+}
+
+func (g *googleDirections) Directions(ctx context.Context, req DirectionsRequest) (DirectionsResponse, error) {
+	var result DirectionsResponse
+
+	mode := req.Mode
+	if mode == "" {
+		mode = "driving"
+	}
+	departure := "now" // 即時出發時間
+	if !req.DepartureTime.IsZero() {
+		departure = strconv.FormatInt(req.DepartureTime.Unix(), 10)
+	}
+
+	params := url.Values{}
+	params.Add("origin", req.Origin)
+	params.Add("destination", req.Destination)
+	params.Add("mode", mode)
+	params.Add("departure_time", departure)
+	params.Add("language", "zh-TW")           // 語言設定為繁體中文
+	params.Add("traffic_model", "best_guess") // 使用最佳交通預測模型
+	params.Add("key", g.apiKey)
+
+	body, err := upstream.Get(ctx, googleMapsUpstreamSource, g.baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return result, newUpstreamDirectionsError(fmt.Errorf("Failed to send request to Google Maps API: %w", err))
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, &DirectionsError{Kind: DirectionsBadResponse, Err: fmt.Errorf("Failed to unmarshal response: %w", err)}
+	}
+	return result, checkDirectionsStatus(result, mode)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// 同一種 Directions 問題重複發出警示的最短間隔
+const directionsAlertInterval = 10 * time.Minute
+
+// DirectionsAlert 記錄最近一次需要管理員處理的 Directions 錯誤，例如 API key 無效或超過配額。
+type DirectionsAlert struct {
+	Status       string    `json:"status"`
+	ErrorMessage string    `json:"errorMessage,omitempty"`
+	Count        int       `json:"count"` // 自啟動以來發生的次數
+	FirstAt      time.Time `json:"firstAt"`
+	LastAt       time.Time `json:"lastAt"`
+	alertedAt    time.Time
+}
+
+// DirectionsAlerts 依 status 彙整 Directions 的警示，並限制 log 的頻率。
+type DirectionsAlerts struct {
+	mu     sync.Mutex
+	alerts map[string]*DirectionsAlert
+}
+
+var directionsAlerts = &DirectionsAlerts{alerts: map[string]*DirectionsAlert{}}
+
+// Record 記錄一次錯誤，距離上次警示超過 directionsAlertInterval 時輸出 [ALERT] log。
+func (a *DirectionsAlerts) Record(err *DirectionsError) {
+	now := time.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	alert, ok := a.alerts[err.Status]
+	if !ok {
+		alert = &DirectionsAlert{Status: err.Status, FirstAt: now}
+		a.alerts[err.Status] = alert
+	}
+	alert.Count++
+	alert.LastAt = now
+	alert.ErrorMessage = err.ErrorMessage
+	if now.Sub(alert.alertedAt) < directionsAlertInterval {
+		return
+	}
+	alert.alertedAt = now
+	log.Printf("[ALERT] %v (%d 次)，請檢查 GOOGLE_MAPS_API_KEY 與配額", err, alert.Count)
+}
+
+// List 回傳所有記錄過的警示。
+func (a *DirectionsAlerts) List() []DirectionsAlert {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	alerts := make([]DirectionsAlert, 0, len(a.alerts))
+	for _, alert := range a.alerts {
+		alerts = append(alerts, *alert)
+	}
+	return alerts
+}
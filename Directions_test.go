@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDirectionsUpstreamFailures(t *testing.T) {
+	useFakeDirections(t)
+
+	tests := []struct {
+		name    string
+		origin  string
+		kind    DirectionsErrorKind
+		message string
+	}{
+		{"slow response", fakeDirectionsSlowPlace, DirectionsTimeout, "Google Maps 回應逾時，請稍後再試"},
+		{"503", fakeDirectionsBrokenPlace, DirectionsServerError, "Google Maps 服務暫時無法使用，請稍後再試"},
+		{"invalid JSON", fakeDirectionsGarbledPlace, DirectionsBadResponse, "無法解析 Google Maps 的回應，請稍後再試"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := getTrafficCondition(tt.origin, "台北101")
+			var directionsErr *DirectionsError
+			if !errors.As(err, &directionsErr) {
+				t.Fatalf("err = %v, want *DirectionsError", err)
+			}
+			if directionsErr.Kind != tt.kind {
+				t.Errorf("kind = %v, want %v (err: %v)", directionsErr.Kind, tt.kind, err)
+			}
+			if !directionsErr.Temporary() {
+				t.Errorf("%v should be temporary", err)
+			}
+			if got := directionsErrorMessage(err); got != tt.message {
+				t.Errorf("message = %q, want %q", got, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const geocodeBaseURL = "https://maps.googleapis.com/maps/api/geocode/json?"
+
+// 未設定 GEOCODE_CACHE_FILE 時的地理編碼快取檔案
+const defaultGeocodeCacheFile = "geocode_cache.json"
+
+// LatLng 為 WGS84 經緯度。
+type LatLng struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// DistanceTo 以 haversine 公式計算兩點距離，單位為公尺。
+func (p LatLng) DistanceTo(q LatLng) float64 {
+	const earthRadius = 6371000.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(q.Lat - p.Lat)
+	dLng := toRad(q.Lng - p.Lng)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(p.Lat))*math.Cos(toRad(q.Lat))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+type geocodeEntry struct {
+	Position LatLng    `json:"position"`
+	Found    bool      `json:"found"`
+	CachedAt time.Time `json:"cachedAt"`
+}
+
+// GeocodeCache 保存地址轉經緯度的結果，查不到的地址也會記錄以免重複查詢。
+type GeocodeCache struct {
+	mu      sync.Mutex
+	entries map[string]geocodeEntry
+	path    string // 快取檔案路徑，空字串表示只保存在記憶體
+	dirty   bool
+}
+
+var geocodeCache = NewGeocodeCache(geocodeCacheFile())
+
+func geocodeCacheFile() string {
+	if path := os.Getenv("GEOCODE_CACHE_FILE"); path != "" {
+		return path
+	}
+	return defaultGeocodeCacheFile
+}
+
+// NewGeocodeCache 建立快取，path 不為空時會載入既有的快取檔。
+func NewGeocodeCache(path string) *GeocodeCache {
+	g := &GeocodeCache{entries: map[string]geocodeEntry{}, path: path}
+	if path == "" {
+		return g
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("讀取地理編碼快取失敗: %v", err)
+		}
+		return g
+	}
+	if err := json.Unmarshal(data, &g.entries); err != nil {
+		log.Printf("解析地理編碼快取失敗: %v", err)
+	}
+	return g
+}
+
+// Lookup 回傳快取中的結果，cached 為 false 表示尚未查詢過。
+func (g *GeocodeCache) Lookup(address string) (position LatLng, found, cached bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	entry, ok := g.entries[address]
+	return entry.Position, entry.Found, ok
+}
+
+// Geocode 將地址轉為經緯度，優先使用快取。
+func (g *GeocodeCache) Geocode(ctx context.Context, address string) (LatLng, bool, error) {
+	if position, found, cached := g.Lookup(address); cached {
+		return position, found, nil
+	}
+
+	position, found, err := geocodeAddress(ctx, address)
+	if err != nil {
+		return LatLng{}, false, err
+	}
+
+	g.mu.Lock()
+	g.entries[address] = geocodeEntry{Position: position, Found: found, CachedAt: time.Now()}
+	g.dirty = true
+	g.mu.Unlock()
+	return position, found, nil
+}
+
+// Save 將新查詢到的結果寫回快取檔。
+func (g *GeocodeCache) Save() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.path == "" || !g.dirty {
+		return
+	}
+	data, err := json.Marshal(g.entries)
+	if err != nil {
+		log.Printf("序列化地理編碼快取失敗: %v", err)
+		return
+	}
+	if err := os.WriteFile(g.path+".tmp", data, 0o644); err != nil {
+		log.Printf("寫入地理編碼快取失敗: %v", err)
+		return
+	}
+	if err := os.Rename(g.path+".tmp", g.path); err != nil {
+		log.Printf("寫入地理編碼快取失敗: %v", err)
+		return
+	}
+	g.dirty = false
+}
+
+type geocodeResponse struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	Results      []struct {
+		FormattedAddress string `json:"formatted_address"`
+		Geometry         struct {
+			Location LatLng `json:"location"`
+		} `json:"geometry"`
+	} `json:"results"`
+}
+
+// geocodeAddress 呼叫 Google Geocoding API，found 為 false 表示查無此地址。
+func geocodeAddress(ctx context.Context, address string) (LatLng, bool, error) {
+	params := url.Values{}
+	params.Add("address", address)
+	params.Add("region", "tw")
+	params.Add("language", "zh-TW")
+	params.Add("key", os.Getenv("GOOGLE_MAPS_API_KEY"))
+
+	result, err := requestGeocode(ctx, params)
+	if err != nil {
+		return LatLng{}, false, err
+	}
+	if len(result.Results) == 0 {
+		return LatLng{}, false, nil
+	}
+	return result.Results[0].Geometry.Location, true, nil
+}
+
+// reverseGeocode 將經緯度轉為地址，用來判斷使用者所在縣市。
+func reverseGeocode(ctx context.Context, position LatLng) (string, error) {
+	params := url.Values{}
+	params.Add("latlng", fmt.Sprintf("%f,%f", position.Lat, position.Lng))
+	params.Add("language", "zh-TW")
+	params.Add("key", os.Getenv("GOOGLE_MAPS_API_KEY"))
+
+	result, err := requestGeocode(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	if len(result.Results) == 0 {
+		return "", nil
+	}
+	return result.Results[0].FormattedAddress, nil
+}
+
+func requestGeocode(ctx context.Context, params url.Values) (geocodeResponse, error) {
+	var result geocodeResponse
+	body, err := upstream.Get(ctx, googleMapsUpstreamSource, geocodeBaseURL+params.Encode(), nil)
+	if err != nil {
+		return result, fmt.Errorf("Failed to send request to Geocoding API: %w", err)
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, fmt.Errorf("Failed to unmarshal response: %w", err)
+	}
+
+	switch result.Status {
+	case "OK", "ZERO_RESULTS":
+		return result, nil
+	}
+	return result, fmt.Errorf("Geocoding API status %s: %s", result.Status, strings.TrimSpace(result.ErrorMessage))
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 未設定 CONSTRUCTION_NEARBY_RADIUS 時的搜尋半徑，單位為公尺
+const defaultNearbyRadius = 1000.0
+
+// 每次查詢最多向 Geocoding API 查詢的新地址數，其餘留待之後的查詢
+const nearbyGeocodeLimit = 40
+
+// 附近施工查詢的時間上限
+const nearbyConstructionTimeout = 15 * time.Second
+
+// NearbyCase 為附近的施工案件與其距離。
+type NearbyCase struct {
+	Case     ConstructionCase
+	Position LatLng
+	Distance float64 // 公尺
+}
+
+// NearbyResult 為附近施工查詢的結果。
+type NearbyResult struct {
+	Source ConstructionSource
+	Cases  []NearbyCase
+	// 還沒有經緯度而未比對距離的施工中案件數，地理編碼快取還沒建立時結果可能不完整
+	Unlocated int
+}
+
+func nearbyRadius() float64 {
+	if r, err := strconv.ParseFloat(os.Getenv("CONSTRUCTION_NEARBY_RADIUS"), 64); err == nil && r > 0 {
+		return r
+	}
+	return defaultNearbyRadius
+}
+
+// findNearbyConstruction 找出 origin 半徑 radius 公尺內目前施工中的案件，依距離排序。
+// address 為使用者分享的地址，用來判斷縣市；地址為空或找不到縣市名稱時 (例如英文介面的
+// "Taipei City")，改以經緯度反查中文地址。找不到縣市時 Source 為 nil。
+func findNearbyConstruction(ctx context.Context, origin LatLng, address string, radius float64) (NearbyResult, error) {
+	var result NearbyResult
+	sources := constructionSourcesMentioned([]string{address})
+	if len(sources) == 0 {
+		reversed, err := reverseGeocode(ctx, origin)
+		if err != nil {
+			return result, err
+		}
+		sources = constructionSourcesMentioned([]string{reversed})
+	}
+	if len(sources) == 0 {
+		return result, nil
+	}
+	result.Source = sources[0]
+
+	snapshot, _, err := constructionCache.Snapshot(ctx, result.Source)
+	if err != nil {
+		return result, err
+	}
+	defer geocodeCache.Save()
+
+	now := time.Now()
+	lookups := 0
+	for _, c := range snapshot.Cases {
+		if !c.ActiveAt(now) || strings.TrimSpace(c.Location) == "" {
+			continue
+		}
+		address := caseGeocodeAddress(c)
+		if _, _, cached := geocodeCache.Lookup(address); !cached {
+			if lookups >= nearbyGeocodeLimit || ctx.Err() != nil {
+				result.Unlocated++
+				continue
+			}
+			lookups++
+		}
+		position, found, err := geocodeCache.Geocode(ctx, address)
+		if err != nil {
+			log.Printf("地址轉換經緯度失敗: %v", err)
+			result.Unlocated++
+			continue
+		}
+		if !found {
+			continue
+		}
+		if distance := origin.DistanceTo(position); distance <= radius {
+			result.Cases = append(result.Cases, NearbyCase{Case: c, Position: position, Distance: distance})
+		}
+	}
+
+	sort.Slice(result.Cases, func(i, j int) bool {
+		return result.Cases[i].Distance < result.Cases[j].Distance
+	})
+	return result, nil
+}
+
+// partialNotice 說明結果不完整的原因，結果完整時回傳空字串。
+func (r NearbyResult) partialNotice() string {
+	if r.Unlocated == 0 {
+		return ""
+	}
+	return fmt.Sprintf("另有 %d 筆施工尚未完成定位，未列入比對，請稍後再查詢一次", r.Unlocated)
+}
+
+// caseGeocodeAddress 為地點文字補上縣市，提高地理編碼的準確度。
+func caseGeocodeAddress(c ConstructionCase) string {
+	location := strings.TrimSpace(c.Location)
+	if strings.Contains(normalizeAddress(location), normalizeAddress(c.County)) {
+		return location
+	}
+	return c.County + location
+}
+
+// formatDistance 以公尺或公里顯示距離
+func formatDistance(meters float64) string {
+	if meters < 1000 {
+		return fmt.Sprintf("%.0f 公尺", meters)
+	}
+	return fmt.Sprintf("%.1f 公里", meters/1000)
+}
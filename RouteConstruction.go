@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// 沿途施工最多列出的案件數
+const routeConstructionLimit = 5
+
+// 查詢沿途施工時等待施工資料的時間上限
+const routeConstructionTimeout = 5 * time.Second
+
+var (
+	// Directions 步驟說明中以 <b> 標示的路名
+	boldTextPattern = regexp.MustCompile(`<b>([^<]+)</b>`)
+	// 可視為道路名稱的結尾
+	roadNamePattern = regexp.MustCompile(`(路|街|大道|道|段|巷|橋|隧道|公路|線|國道\d+號)$`)
+	// 路名後的段號，比對時只取路名本身
+	roadSectionPattern = regexp.MustCompile(`\d+段$`)
+)
+
+// extractRouteRoads 從 Directions 步驟的 html_instructions 取出不重複的路名，
+// 例如 "向<b>東</b>，朝<b>忠孝東路四段</b>前進" 會得到 "忠孝東路"。
+func extractRouteRoads(instructions []string) []string {
+	seen := map[string]bool{}
+	var roads []string
+	for _, instruction := range instructions {
+		for _, m := range boldTextPattern.FindAllStringSubmatch(instruction, -1) {
+			for _, name := range strings.Split(html.UnescapeString(m[1]), "/") {
+				road := normalizeAddress(name)
+				if !roadNamePattern.MatchString(road) {
+					continue
+				}
+				road = roadSectionPattern.ReplaceAllString(road, "")
+				if len([]rune(road)) < 2 || seen[road] {
+					continue
+				}
+				seen[road] = true
+				roads = append(roads, road)
+			}
+		}
+	}
+	return roads
+}
+
+// findRouteConstruction 找出路線經過縣市中，地點包含沿途路名且仍在施工期間的案件。
+func findRouteConstruction(sources []ConstructionSource, roads []string, now time.Time) []ConstructionCase {
+	if len(sources) == 0 || len(roads) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), routeConstructionTimeout)
+	defer cancel()
+
+	var matched []ConstructionCase
+	for _, source := range sources {
+		snapshot, _, err := constructionCache.Snapshot(ctx, source)
+		if err != nil {
+			log.Printf("%s 取得沿途施工資料失敗: %v", source.County(), err)
+			continue
+		}
+		for _, c := range snapshot.Cases {
+			if !c.ActiveAt(now) {
+				continue
+			}
+			location := normalizeAddress(c.Location)
+			for _, road := range roads {
+				if strings.Contains(location, road) {
+					matched = append(matched, c)
+					break
+				}
+			}
+		}
+	}
+	return matched
+}
+
+// routeConstructionContents 將沿途施工案件組成 Flex Message 的區塊。
+func routeConstructionContents(cases []ConstructionCase) []map[string]interface{} {
+	contents := []map[string]interface{}{
+		{
+			"type":   "separator",
+			"margin": "lg",
+		},
+		{
+			"type":   "text",
+			"text":   "沿途施工",
+			"size":   "md",
+			"weight": "bold",
+			"color":  "#E67E22",
+			"margin": "lg",
+		},
+	}
+
+	more := len(cases) - routeConstructionLimit
+	if more > 0 {
+		cases = cases[:routeConstructionLimit]
+	}
+	for _, c := range cases {
+		contents = append(contents, map[string]interface{}{
+			"type":   "text",
+			"text":   fmt.Sprintf("%s %s\n%s", c.County, c.Location, c.Period()),
+			"size":   "xs",
+			"color":  "#555555",
+			"wrap":   true,
+			"margin": "sm",
+		})
+	}
+	if more > 0 {
+		contents = append(contents, map[string]interface{}{
+			"type":   "text",
+			"text":   fmt.Sprintf("另有 %d 筆施工，請以道路施工查詢查看", more),
+			"size":   "xxs",
+			"color":  "#888888",
+			"margin": "sm",
+		})
+	}
+	return contents
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot"
+)
+
+// 每個聊天對象最多可建立的訂閱數
+const subscriptionLimitPerTarget = 10
+
+// 每則推播最多列出的案件數
+const subscriptionPushLimit = 5
+
+// Subscription 為一筆道路施工訂閱，符合條件的新案件或明天開工的案件會推播給 Target。
+type Subscription struct {
+	ID        int               `json:"id"`
+	Target    string            `json:"target"` // 推播對象的 user、group 或 room ID
+	Query     ConstructionQuery `json:"query"`
+	CreatedAt time.Time         `json:"createdAt"`
+	// Notified 記錄已推播過的提醒，避免重複通知
+	Notified map[string]bool `json:"notified,omitempty"`
+}
+
+func (s Subscription) String() string {
+	conditions := append([]string{s.Query.County}, s.Query.District)
+	conditions = append(conditions, s.Query.Keywords...)
+	return fmt.Sprintf("#%d %s", s.ID, strings.Join(strings.Fields(strings.Join(conditions, " ")), " "))
+}
+
+// SubscriptionStore 保存所有訂閱，設定 path 時會同步寫入 JSON 檔。
+type SubscriptionStore struct {
+	mu            sync.Mutex
+	subscriptions []Subscription
+	nextID        int
+	path          string
+}
+
+var subscriptionStore = NewSubscriptionStore(os.Getenv("SUBSCRIPTION_FILE"))
+
+// NewSubscriptionStore 建立訂閱儲存，path 不為空時會載入既有的訂閱。
+func NewSubscriptionStore(path string) *SubscriptionStore {
+	s := &SubscriptionStore{path: path, nextID: 1}
+	if path == "" {
+		return s
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("讀取訂閱檔失敗: %v", err)
+		}
+		return s
+	}
+	if err := json.Unmarshal(data, &s.subscriptions); err != nil {
+		log.Printf("解析訂閱檔失敗: %v", err)
+	}
+	for _, sub := range s.subscriptions {
+		if sub.ID >= s.nextID {
+			s.nextID = sub.ID + 1
+		}
+	}
+	return s
+}
+
+// Add 新增訂閱，超過每個對象的上限時回傳錯誤。
+func (s *SubscriptionStore) Add(target string, query ConstructionQuery) (Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, sub := range s.subscriptions {
+		if sub.Target == target {
+			count++
+		}
+	}
+	if count >= subscriptionLimitPerTarget {
+		return Subscription{}, fmt.Errorf("最多只能建立 %d 筆訂閱", subscriptionLimitPerTarget)
+	}
+
+	sub := Subscription{
+		ID:        s.nextID,
+		Target:    target,
+		Query:     query,
+		CreatedAt: time.Now(),
+	}
+	s.nextID++
+	s.subscriptions = append(s.subscriptions, sub)
+	s.save()
+	return sub, nil
+}
+
+// Remove 刪除 target 的某筆訂閱，找不到時回傳 false。
+func (s *SubscriptionStore) Remove(target string, id int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, sub := range s.subscriptions {
+		if sub.Target == target && sub.ID == id {
+			s.subscriptions = append(s.subscriptions[:i], s.subscriptions[i+1:]...)
+			s.save()
+			return true
+		}
+	}
+	return false
+}
+
+// List 回傳 target 的所有訂閱。
+func (s *SubscriptionStore) List(target string) []Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var subs []Subscription
+	for _, sub := range s.subscriptions {
+		if sub.Target == target {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+// save 需在持有 mu 時呼叫。
+func (s *SubscriptionStore) save() {
+	if s.path == "" {
+		return
+	}
+	data, err := json.Marshal(s.subscriptions)
+	if err != nil {
+		log.Printf("序列化訂閱失敗: %v", err)
+		return
+	}
+	if err := os.WriteFile(s.path+".tmp", data, 0o644); err != nil {
+		log.Printf("寫入訂閱檔失敗: %v", err)
+		return
+	}
+	if err := os.Rename(s.path+".tmp", s.path); err != nil {
+		log.Printf("寫入訂閱檔失敗: %v", err)
+	}
+}
+
+// subscriptionNotice 為要推播給某個對象的內容。
+type subscriptionNotice struct {
+	Target       string
+	Subscription Subscription
+	NewCases     []ConstructionCase
+	Tomorrow     []ConstructionCase
+}
+
+// Diff 比對同一縣市前後兩份快照，找出每筆訂閱新出現的案件與明天開工的案件。
+// previous 為空時 (第一次取得資料) 只檢查明天開工的案件，避免把所有案件當成新案件推播。
+func (s *SubscriptionStore) Diff(previous, current ConstructionSnapshot, hasPrevious bool, now time.Time) []subscriptionNotice {
+	seen := map[string]bool{}
+	for _, c := range previous.Cases {
+		seen[c.Key()] = true
+	}
+	currentKeys := map[string]bool{}
+	for _, c := range current.Cases {
+		currentKeys[c.Key()] = true
+	}
+
+	tomorrowFrom, tomorrowTo, _ := parseDateWindow("明天", now)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var notices []subscriptionNotice
+	for i := range s.subscriptions {
+		sub := &s.subscriptions[i]
+		if source, ok := LookupConstructionSource(sub.Query.County); !ok || source.County() != current.County {
+			continue
+		}
+
+		notice := subscriptionNotice{Target: sub.Target, Subscription: *sub}
+		for _, c := range FilterConstructionCases(current.Cases, sub.Query, now) {
+			key := c.Key()
+			if hasPrevious && !seen[key] && !sub.Notified["new:"+key] {
+				notice.NewCases = append(notice.NewCases, c)
+				sub.markNotified("new:" + key)
+			}
+			if !c.Start.Before(tomorrowFrom) && c.Start.Before(tomorrowTo) && !sub.Notified["tomorrow:"+key] {
+				notice.Tomorrow = append(notice.Tomorrow, c)
+				sub.markNotified("tomorrow:" + key)
+			}
+		}
+
+		// 只保留仍在快照中的案件紀錄，避免無限增長
+		for key := range sub.Notified {
+			if !currentKeys[key[strings.Index(key, ":")+1:]] {
+				delete(sub.Notified, key)
+			}
+		}
+
+		if len(notice.NewCases) > 0 || len(notice.Tomorrow) > 0 {
+			notices = append(notices, notice)
+		}
+	}
+	s.save()
+	return notices
+}
+
+func (s *Subscription) markNotified(key string) {
+	if s.Notified == nil {
+		s.Notified = map[string]bool{}
+	}
+	s.Notified[key] = true
+}
+
+func (n subscriptionNotice) String() string {
+	reply := fmt.Sprintf("施工訂閱通知 %s\n\n", n.Subscription)
+	writeCases := func(title string, cases []ConstructionCase) {
+		if len(cases) == 0 {
+			return
+		}
+		reply += fmt.Sprintf("【%s】共 %d 筆\n\n", title, len(cases))
+		for idx, c := range cases {
+			if idx >= subscriptionPushLimit {
+				reply += fmt.Sprintf("另有 %d 筆，請以道路施工查詢查看\n\n", len(cases)-subscriptionPushLimit)
+				break
+			}
+			reply += c.String() + "\n"
+		}
+	}
+	writeCases("新增施工", n.NewCases)
+	writeCases("明天開工", n.Tomorrow)
+	return strings.TrimSpace(reply)
+}
+
+// notifySubscribers 在快照更新後推播訂閱通知，作為 ConstructionCache 的更新監聽函式。
+func notifySubscribers(previous, current ConstructionSnapshot, hasPrevious bool) {
+	if bot == nil {
+		return
+	}
+	for _, notice := range subscriptionStore.Diff(previous, current, hasPrevious, time.Now()) {
+		if _, err := bot.PushMessage(notice.Target, linebot.NewTextMessage(notice.String())).Do(); err != nil {
+			log.Printf("推播訂閱通知失敗: %v", err)
+		}
+	}
+}
+
+// handleSubscriptionCommand 處理 施工訂閱、訂閱清單、取消訂閱 指令，回傳要回覆的文字。
+func handleSubscriptionCommand(function string, target string, args []string) string {
+	if target == "" {
+		return "無法取得使用者資訊，請稍後再試"
+	}
+
+	switch function {
+	case "施工訂閱":
+		if len(args) < 2 {
+			return "指令格式錯誤，請輸入:\n施工訂閱\n[縣市名稱]\n[行政區 路名]"
+		}
+		query := ParseConstructionQuery(args)
+		// 沒有公開資料的縣市永遠不會有通知，不建立訂閱
+		source, err := resolveConstructionSource(query.County)
+		if err != nil {
+			return constructionErrorMessage(err)
+		}
+		query.County = source.County()
+		// 訂閱依據路名與行政區比對，日期條件沒有意義
+		query.Window = ""
+		if !query.Filtered() {
+			return "請至少輸入一個行政區或路名"
+		}
+		sub, err := subscriptionStore.Add(target, query)
+		if err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("已建立訂閱 %s\n有新的施工案件或明天開工的案件時會通知您", sub)
+
+	case "訂閱清單":
+		subs := subscriptionStore.List(target)
+		if len(subs) == 0 {
+			return "目前沒有任何施工訂閱"
+		}
+		sort.Slice(subs, func(i, j int) bool { return subs[i].ID < subs[j].ID })
+		var lines []string
+		for _, sub := range subs {
+			lines = append(lines, sub.String())
+		}
+		return "目前的施工訂閱:\n" + strings.Join(lines, "\n")
+
+	case "取消訂閱":
+		if len(args) != 1 {
+			return "指令格式錯誤，請輸入:\n取消訂閱\n[訂閱編號]"
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(args[0]), "#"))
+		if err != nil || !subscriptionStore.Remove(target, id) {
+			return "找不到此訂閱編號，請以 訂閱清單 查詢"
+		}
+		return fmt.Sprintf("已取消訂閱 #%d", id)
+	}
+	return ""
+}
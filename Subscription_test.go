@@ -0,0 +1,111 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSubscriptionDiff(t *testing.T) {
+	now := taipeiDate(2024, 12, 25, 9, 0)
+	active := ConstructionCase{County: "台北市", CaseID: "active", Location: "大安區忠孝東路四段1號",
+		Start: taipeiDate(2024, 12, 1, 0, 0), End: taipeiDate(2024, 12, 31, 0, 0)}
+	tomorrow := ConstructionCase{County: "台北市", CaseID: "tomorrow", Location: "大安區忠孝東路四段2號",
+		Start: taipeiDate(2024, 12, 26, 9, 0), End: taipeiDate(2024, 12, 27, 0, 0)}
+	added := ConstructionCase{County: "台北市", CaseID: "added", Location: "大安區忠孝東路四段3號",
+		Start: taipeiDate(2025, 1, 2, 0, 0), End: taipeiDate(2025, 1, 9, 0, 0)}
+	elsewhere := ConstructionCase{County: "台北市", CaseID: "elsewhere", Location: "士林區中山北路五段",
+		Start: taipeiDate(2024, 12, 26, 0, 0), End: taipeiDate(2024, 12, 27, 0, 0)}
+	snapshot := func(cases ...ConstructionCase) ConstructionSnapshot {
+		return ConstructionSnapshot{County: "台北市", Cases: cases, UpdatedAt: now}
+	}
+	ids := func(cases []ConstructionCase) string {
+		var ids []string
+		for _, c := range cases {
+			ids = append(ids, c.CaseID)
+		}
+		return strings.Join(ids, ",")
+	}
+
+	store := NewSubscriptionStore("")
+	sub, err := store.Add("U1", ConstructionQuery{County: "台北市", Keywords: []string{"忠孝東路"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 其他縣市的訂閱不受台北市的快照影響
+	if _, err := store.Add("U2", ConstructionQuery{County: "新北市", Keywords: []string{"忠孝東路"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name              string
+		previous, current ConstructionSnapshot
+		hasPrevious       bool
+		newCases          string
+		tomorrow          string
+	}{
+		{
+			// 第一次取得資料不把既有案件當成新案件，但仍提醒明天開工的案件
+			name:    "first load",
+			current: snapshot(active, tomorrow, elsewhere), hasPrevious: false,
+			tomorrow: "tomorrow",
+		},
+		{
+			name:     "new case",
+			previous: snapshot(active, tomorrow, elsewhere), current: snapshot(active, tomorrow, added, elsewhere), hasPrevious: true,
+			newCases: "added",
+		},
+		{
+			name:     "no repeated notifications",
+			previous: snapshot(active, tomorrow), current: snapshot(active, tomorrow, added), hasPrevious: true,
+		},
+	}
+	for _, step := range steps {
+		notices := store.Diff(step.previous, step.current, step.hasPrevious, now)
+		if step.newCases == "" && step.tomorrow == "" {
+			if len(notices) != 0 {
+				t.Errorf("%s: unexpected notices %+v", step.name, notices)
+			}
+			continue
+		}
+		if len(notices) != 1 || notices[0].Target != "U1" || notices[0].Subscription.ID != sub.ID {
+			t.Fatalf("%s: notices = %+v", step.name, notices)
+		}
+		if got := ids(notices[0].NewCases); got != step.newCases {
+			t.Errorf("%s: new cases = %q, want %q", step.name, got, step.newCases)
+		}
+		if got := ids(notices[0].Tomorrow); got != step.tomorrow {
+			t.Errorf("%s: tomorrow = %q, want %q", step.name, got, step.tomorrow)
+		}
+	}
+
+	// 案件從快照消失後刪除推播紀錄
+	store.Diff(snapshot(active, tomorrow, added), snapshot(active), true, now)
+	notified := store.List("U1")[0].Notified
+	if len(notified) != 0 {
+		t.Errorf("notified = %v, want pruned", notified)
+	}
+	// 已刪除紀錄的案件再次出現時視為新案件
+	notices := store.Diff(snapshot(active), snapshot(active, added), true, now)
+	if len(notices) != 1 || ids(notices[0].NewCases) != "added" {
+		t.Errorf("reappeared case: notices = %+v", notices)
+	}
+}
+
+func TestSubscribeRejectsCountiesWithoutFeed(t *testing.T) {
+	store := subscriptionStore
+	subscriptionStore = NewSubscriptionStore("")
+	t.Cleanup(func() { subscriptionStore = store })
+
+	reply := handleSubscriptionCommand("施工訂閱", "U1", []string{"新竹市", "東區 光復路"})
+	if !strings.HasPrefix(reply, "新竹市目前沒有公開的道路施工資料") {
+		t.Errorf("reply = %q", reply)
+	}
+	if subs := subscriptionStore.List("U1"); len(subs) != 0 {
+		t.Errorf("subscriptions = %+v, want none", subs)
+	}
+
+	reply = handleSubscriptionCommand("施工訂閱", "U1", []string{"台北市", "大安區 忠孝東路"})
+	if !strings.HasPrefix(reply, "已建立訂閱 #1 台北市 大安區 忠孝東路") {
+		t.Errorf("reply = %q", reply)
+	}
+}
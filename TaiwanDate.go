@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 台灣無日光節約時間，固定使用 UTC+8
+var taipeiLocation = time.FixedZone("Asia/Taipei", 8*60*60)
+
+// 民國紀年與西元紀年的差距
+const rocYearOffset = 1911
+
+var (
+	// 113年12月25日、民國113年12月25日、2024年12月25日
+	chineseDatePattern = regexp.MustCompile(`^(?:民國)?(\d{2,4})年(\d{1,2})月(\d{1,2})日?`)
+	// 2024-12-25、2024/12/25、113/12/25、113.12.25
+	separatedDatePattern = regexp.MustCompile(`^(\d{2,4})[-/.](\d{1,2})[-/.](\d{1,2})`)
+	// 20241225、1131225、991225
+	compactDatePattern = regexp.MustCompile(`^(\d{6,8})`)
+	// 08:30、8:30:15、上午08:30、下午 2:05
+	clockPattern = regexp.MustCompile(`^(上午|下午|AM|PM|am|pm)?(\d{1,2}):(\d{2})(?::(\d{2}))?(?:\.\d+)?(上午|下午|AM|PM|am|pm)?`)
+	// 區間的分隔字，"自X至Y" 的 "自" 會先被移除
+	dateRangeSeparators = []string{"至", "~", "～", "到", " - "}
+	// "X起至Y止" 中起日與迄日後的標記字
+	startMarkers = []string{"起", "開始"}
+	endMarkers   = []string{"為止", "止", "結束"}
+)
+
+var fullWidthReplacer = strings.NewReplacer(
+	"０", "0", "１", "1", "２", "2", "３", "3", "４", "4",
+	"５", "5", "６", "6", "７", "7", "８", "8", "９", "9",
+	"／", "/", "：", ":", "．", ".", "－", "-", "　", " ",
+)
+
+// ParseTaiwanDate 解析施工資料中常見的單一日期或日期時間，回傳 Asia/Taipei 時區的時間。
+// 支援 7 碼民國日期 (1131225)、民國斜線 (113/12/25)、民國年月日 (民國113年12月25日)、
+// 西元 ISO (2024-12-25、2024/12/25、20241225) 以及附帶時間的寫法
+// (2024-12-25 08:30:00、2024-12-25T08:30:00+08:00、113/12/25 下午 02:00)。
+func ParseTaiwanDate(s string) (time.Time, error) {
+	text := strings.TrimSpace(fullWidthReplacer.Replace(s))
+	if text == "" {
+		return time.Time{}, fmt.Errorf("空白日期")
+	}
+
+	// 帶時區的 ISO 8601 直接交給標準函式庫
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return t.In(taipeiLocation), nil
+	}
+
+	year, month, day, rest, ok := matchTaiwanDate(text)
+	if !ok {
+		return time.Time{}, fmt.Errorf("無法解析日期 %q", s)
+	}
+
+	hour, minute, second, ok := matchClock(rest)
+	if !ok {
+		return time.Time{}, fmt.Errorf("無法解析時間 %q", s)
+	}
+
+	t := time.Date(year, time.Month(month), day, hour, minute, second, 0, taipeiLocation)
+	if t.Year() != year || int(t.Month()) != month || t.Day() != day {
+		return time.Time{}, fmt.Errorf("日期不存在 %q", s)
+	}
+	return t, nil
+}
+
+// ParseTaiwanDateRange 解析 "自1131201至1131231"、"113/12/01 ~ 113/12/31"、
+// "2024-12-01 至 2024-12-31"、"113年12月1日起至113年12月31日止" 等期間文字。
+// 只有一個日期時 end 為零值。
+func ParseTaiwanDateRange(s string) (start, end time.Time, err error) {
+	text := strings.TrimSpace(fullWidthReplacer.Replace(s))
+	text = trimMarkers(strings.TrimSpace(strings.TrimPrefix(text, "自")), endMarkers)
+
+	for _, sep := range dateRangeSeparators {
+		parts := strings.SplitN(text, sep, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		start, err = ParseTaiwanDate(trimMarkers(parts[0], startMarkers))
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		endText := strings.TrimSpace(parts[1])
+		end, err = ParseTaiwanDate(endText)
+		if err != nil {
+			// "113/12/25 09:00~17:00" 的迄時只有時間，沿用起日
+			hour, minute, second, ok := matchClock(endText)
+			if !ok || endText == "" {
+				return time.Time{}, time.Time{}, err
+			}
+			end = time.Date(start.Year(), start.Month(), start.Day(), hour, minute, second, 0, taipeiLocation)
+		}
+		return start, end, nil
+	}
+
+	start, err = ParseTaiwanDate(trimMarkers(text, startMarkers))
+	if err == nil {
+		return start, time.Time{}, nil
+	}
+
+	// "113.12.01-113.12.31" 以沒有空白的 "-" 分隔，逐一嘗試兩邊都能解析的位置
+	for i := strings.Index(text, "-"); i >= 0; {
+		left, errLeft := ParseTaiwanDate(text[:i])
+		right, errRight := ParseTaiwanDate(text[i+1:])
+		if errLeft == nil && errRight == nil {
+			return left, right, nil
+		}
+		next := strings.Index(text[i+1:], "-")
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return time.Time{}, time.Time{}, err
+}
+
+// trimMarkers 移除字串結尾的起迄標記字，例如 "113年12月1日起" 的 "起"。
+func trimMarkers(s string, markers []string) string {
+	s = strings.TrimSpace(s)
+	for _, marker := range markers {
+		if strings.HasSuffix(s, marker) {
+			return strings.TrimSpace(strings.TrimSuffix(s, marker))
+		}
+	}
+	return s
+}
+
+// matchTaiwanDate 從字串開頭取出年月日，民國年會轉為西元年，rest 為剩下的時間部分。
+func matchTaiwanDate(text string) (year, month, day int, rest string, ok bool) {
+	var digits []string
+	if m := chineseDatePattern.FindStringSubmatch(text); m != nil {
+		digits, rest = m[1:], text[len(m[0]):]
+	} else if m := separatedDatePattern.FindStringSubmatch(text); m != nil {
+		digits, rest = m[1:], text[len(m[0]):]
+	} else if m := compactDatePattern.FindStringSubmatch(text); m != nil {
+		rest = text[len(m[0]):]
+		switch n := m[1]; len(n) {
+		case 8:
+			digits = []string{n[:4], n[4:6], n[6:]}
+		case 7:
+			digits = []string{n[:3], n[3:5], n[5:]}
+		case 6:
+			digits = []string{n[:2], n[2:4], n[4:]}
+		}
+	} else {
+		return 0, 0, 0, "", false
+	}
+
+	year, _ = strconv.Atoi(digits[0])
+	month, _ = strconv.Atoi(digits[1])
+	day, _ = strconv.Atoi(digits[2])
+	// 三位數以下的年份視為民國年
+	if year <= 999 {
+		year += rocYearOffset
+	}
+	return year, month, day, rest, true
+}
+
+// matchClock 解析日期後的時間部分，空字串視為 00:00:00。
+func matchClock(rest string) (hour, minute, second int, ok bool) {
+	rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), "T"))
+	if rest == "" {
+		return 0, 0, 0, true
+	}
+
+	m := clockPattern.FindStringSubmatch(strings.ReplaceAll(rest, " ", ""))
+	if m == nil {
+		return 0, 0, 0, false
+	}
+	hour, _ = strconv.Atoi(m[2])
+	minute, _ = strconv.Atoi(m[3])
+	if m[4] != "" {
+		second, _ = strconv.Atoi(m[4])
+	}
+
+	meridiem := m[1] + m[5]
+	switch strings.ToUpper(meridiem) {
+	case "下午", "PM":
+		if hour < 12 {
+			hour += 12
+		}
+	case "上午", "AM":
+		if hour == 12 {
+			hour = 0
+		}
+	}
+
+	if hour > 23 || minute > 59 || second > 59 {
+		return 0, 0, 0, false
+	}
+	return hour, minute, second, true
+}
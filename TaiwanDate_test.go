@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTaiwanDate(t *testing.T) {
+	tests := []struct {
+		feed  string
+		input string
+		want  time.Time
+	}{
+		{"高雄市 DATE_DIG_S", "1131202", taipeiDate(2024, 12, 2, 0, 0)},
+		{"屏東縣 核准施工起始日期", "1131201", taipeiDate(2024, 12, 1, 0, 0)},
+		{"屏東縣 兩位數民國年", "991231", taipeiDate(2010, 12, 31, 0, 0)},
+		{"宜蘭縣 ABE_DA", "2024-12-03", taipeiDate(2024, 12, 3, 0, 0)},
+		{"宜蘭縣 ABE_DA 含時間", "2024-12-03T00:00:00", taipeiDate(2024, 12, 3, 0, 0)},
+		{"金門縣 SchedStart", "2024-12-02T08:30:00", taipeiDate(2024, 12, 2, 8, 30)},
+		{"金門縣 SchedStart 上午下午", "2024/12/02 下午 02:00:00", taipeiDate(2024, 12, 2, 14, 0)},
+		{"新北市 CaseStart", "2024/12/01", taipeiDate(2024, 12, 1, 0, 0)},
+		{"桃園市 Start", "2024-12-01 09:00:00", taipeiDate(2024, 12, 1, 9, 0)},
+		{"台中市 核准起日", "1131201", taipeiDate(2024, 12, 1, 0, 0)},
+		{"苗栗縣 施工起日", "113/12/02", taipeiDate(2024, 12, 2, 0, 0)},
+		{"民國年月日", "民國113年12月25日", taipeiDate(2024, 12, 25, 0, 0)},
+		{"全形數字", "１１３／１２／２５", taipeiDate(2024, 12, 25, 0, 0)},
+		{"帶時區", "2024-12-25T08:30:00Z", taipeiDate(2024, 12, 25, 16, 30)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTaiwanDate(tt.input)
+		if err != nil {
+			t.Errorf("%s: ParseTaiwanDate(%q) error: %v", tt.feed, tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: ParseTaiwanDate(%q) = %v, want %v", tt.feed, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseTaiwanDateInvalid(t *testing.T) {
+	for _, input := range []string{"", "無資料", "1130231", "113/13/01", "2024-12-25 25:00"} {
+		if got, err := ParseTaiwanDate(input); err == nil {
+			t.Errorf("ParseTaiwanDate(%q) = %v, want error", input, got)
+		}
+	}
+}
+
+func TestParseTaiwanDateRange(t *testing.T) {
+	tests := []struct {
+		feed       string
+		input      string
+		start, end time.Time
+	}{
+		{"台北市 AppTime", "113/12/25 09:00~113/12/31 17:00", taipeiDate(2024, 12, 25, 9, 0), taipeiDate(2024, 12, 31, 17, 0)},
+		{"台北市 AppTime 同日時段", "113/12/26 09:00~17:00", taipeiDate(2024, 12, 26, 9, 0), taipeiDate(2024, 12, 26, 17, 0)},
+		{"新竹縣 施工期間", "113/12/01~113/12/15", taipeiDate(2024, 12, 1, 0, 0), taipeiDate(2024, 12, 15, 0, 0)},
+		{"新竹縣 施工期間 起止", "113年12月1日起至113年12月31日止", taipeiDate(2024, 12, 1, 0, 0), taipeiDate(2024, 12, 31, 0, 0)},
+		{"新竹縣 施工期間 為止", "113年12月1日起至114年1月5日為止", taipeiDate(2024, 12, 1, 0, 0), taipeiDate(2025, 1, 5, 0, 0)},
+		{"嘉義縣 日期", "自113/12/02至113/12/18", taipeiDate(2024, 12, 2, 0, 0), taipeiDate(2024, 12, 18, 0, 0)},
+		{"嘉義縣 日期 7 碼", "自1131201至1131231", taipeiDate(2024, 12, 1, 0, 0), taipeiDate(2024, 12, 31, 0, 0)},
+		{"花蓮縣 施工日期", "113/12/02~113/12/20", taipeiDate(2024, 12, 2, 0, 0), taipeiDate(2024, 12, 20, 0, 0)},
+		{"花蓮縣 施工日期 點分隔", "113.12.01-113.12.31", taipeiDate(2024, 12, 1, 0, 0), taipeiDate(2024, 12, 31, 0, 0)},
+		{"西元區間", "2024-12-01 至 2024-12-31", taipeiDate(2024, 12, 1, 0, 0), taipeiDate(2024, 12, 31, 0, 0)},
+		{"只有起日", "113年12月1日起", taipeiDate(2024, 12, 1, 0, 0), time.Time{}},
+	}
+	for _, tt := range tests {
+		start, end, err := ParseTaiwanDateRange(tt.input)
+		if err != nil {
+			t.Errorf("%s: ParseTaiwanDateRange(%q) error: %v", tt.feed, tt.input, err)
+			continue
+		}
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("%s: ParseTaiwanDateRange(%q) = %v ~ %v, want %v ~ %v", tt.feed, tt.input, start, end, tt.start, tt.end)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// CongestionLevel 為依塞車延誤比例分級的路況。
+type CongestionLevel int
+
+const (
+	CongestionSmooth  CongestionLevel = iota // 順暢
+	CongestionBusy                           // 車多
+	CongestionHeavy                          // 壅塞
+	CongestionSevere                         // 嚴重壅塞
+	CongestionUnknown                        // 沒有即時路況資料
+)
+
+// 各級距的延誤比例上限 (塞車時間 / 平常時間)，超過最後一級為嚴重壅塞
+var congestionThresholds = []struct {
+	level    CongestionLevel
+	maxRatio float64
+}{
+	{CongestionSmooth, 1.1},
+	{CongestionBusy, 1.3},
+	{CongestionHeavy, 1.6},
+}
+
+func (l CongestionLevel) String() string {
+	switch l {
+	case CongestionSmooth:
+		return "順暢"
+	case CongestionBusy:
+		return "車多"
+	case CongestionHeavy:
+		return "壅塞"
+	case CongestionUnknown:
+		return "無即時路況"
+	}
+	return "嚴重壅塞"
+}
+
+// Color 回傳路況卡片使用的顏色。
+func (l CongestionLevel) Color() string {
+	switch l {
+	case CongestionSmooth:
+		return "#27AE60"
+	case CongestionBusy:
+		return "#F1C40F"
+	case CongestionHeavy:
+		return "#E67E22"
+	case CongestionUnknown:
+		return "#7F8C8D"
+	}
+	return "#C0392B"
+}
+
+// classifyCongestion 依平常與塞車時的秒數計算延誤比例並分級。
+func classifyCongestion(regularSeconds, trafficSeconds int) CongestionLevel {
+	if regularSeconds <= 0 {
+		return CongestionSmooth
+	}
+	ratio := float64(trafficSeconds) / float64(regularSeconds)
+	for _, t := range congestionThresholds {
+		if ratio < t.maxRatio {
+			return t.level
+		}
+	}
+	return CongestionSevere
+}
+
+// TrafficCondition 為起訖點之間目前的開車路況。
+type TrafficCondition struct {
+	Origin      string
+	Destination string
+	Regular     TextValue // 平常的開車時間
+	InTraffic   TextValue // 考慮目前路況的開車時間，沒有即時路況資料時為零值
+	Level       CongestionLevel
+}
+
+// newTrafficCondition 由 Directions 的路段建立路況。沒有 duration_in_traffic 的地區
+// 無法判斷目前路況，等級為 CongestionUnknown，不視為順暢。
+func newTrafficCondition(origin, destination string, leg DirectionsLeg) TrafficCondition {
+	condition := TrafficCondition{
+		Origin:      origin,
+		Destination: destination,
+		Regular:     leg.Duration,
+		Level:       CongestionUnknown,
+	}
+	if leg.DurationInTraffic != nil {
+		condition.InTraffic = *leg.DurationInTraffic
+		condition.Level = classifyCongestion(leg.Duration.Value, condition.InTraffic.Value)
+	}
+	return condition
+}
+
+// DelayMinutes 回傳比平常多花的分鐘數，四捨五入，比平常快時為 0。
+func (c TrafficCondition) DelayMinutes() int {
+	if c.Level == CongestionUnknown {
+		return 0
+	}
+	delay := c.InTraffic.Value - c.Regular.Value
+	if delay <= 0 {
+		return 0
+	}
+	return int(math.Round(float64(delay) / 60))
+}
+
+func (c TrafficCondition) String() string {
+	reply := fmt.Sprintf("起點: %s\n終點: %s\n\n", c.Origin, c.Destination)
+	if c.Level == CongestionUnknown {
+		return reply + fmt.Sprintf("路況: 目前無法取得即時路況資料\n平常開車時間:%s", c.Regular.Text)
+	}
+	reply += fmt.Sprintf("路況: %s\n", c.Level)
+	if delay := c.DelayMinutes(); delay > 0 {
+		reply += fmt.Sprintf("平常開車時間:%s\n現在開車時間:%s (多 %d 分鐘)", c.Regular.Text, c.InTraffic.Text, delay)
+	} else {
+		reply += fmt.Sprintf("開車時間約為:%s", c.InTraffic.Text)
+	}
+	return reply
+}
+
+// trafficConditionBubble 以路況等級的顏色呈現即時路況。
+func trafficConditionBubble(c TrafficCondition) map[string]interface{} {
+	delay, now := "與平常相同", c.InTraffic.Text
+	if c.Level == CongestionUnknown {
+		delay, now = "目前無法取得即時路況資料", "無資料"
+	} else if minutes := c.DelayMinutes(); minutes > 0 {
+		delay = fmt.Sprintf("比平常多 %d 分鐘", minutes)
+	}
+	row := func(label, value string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":   "box",
+			"layout": "baseline",
+			"margin": "sm",
+			"contents": []map[string]interface{}{
+				{"type": "text", "text": label, "size": "sm", "color": "#AAAAAA", "flex": 2},
+				{"type": "text", "text": value, "size": "sm", "color": "#555555", "wrap": true, "flex": 5},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"type": "bubble",
+		"header": map[string]interface{}{
+			"type":            "box",
+			"layout":          "vertical",
+			"backgroundColor": c.Level.Color(),
+			"paddingAll":      "16px",
+			"contents": []map[string]interface{}{
+				{
+					"type":  "text",
+					"text":  "即時路況",
+					"size":  "sm",
+					"color": "#ffffffcc",
+				},
+				{
+					"type":   "text",
+					"text":   c.Level.String(),
+					"size":   "xxl",
+					"color":  "#ffffff",
+					"weight": "bold",
+				},
+				{
+					"type":  "text",
+					"text":  delay,
+					"size":  "sm",
+					"color": "#ffffff",
+				},
+			},
+		},
+		"body": map[string]interface{}{
+			"type":   "box",
+			"layout": "vertical",
+			"contents": []map[string]interface{}{
+				row("起點", c.Origin),
+				row("終點", c.Destination),
+				{"type": "separator", "margin": "md"},
+				row("平常", c.Regular.Text),
+				row("現在", now),
+			},
+		},
+	}
+}
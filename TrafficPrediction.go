@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// 預測的時段間隔，時段對齊整點，例如 00:00、02:00
+	predictedTrafficSlotHours = 2
+	// 同時查詢的時段數
+	predictedTrafficWorkers = 4
+	// 整個預測的時間上限，逾時後以已取得的時段回覆
+	predictedTrafficTimeout = 20 * time.Second
+)
+
+// TrafficSlot 為一個出發時段的預測開車時間。
+type TrafficSlot struct {
+	Departure time.Time // 出發時間 (台北時間整點)
+	Seconds   int       // 考慮路況的開車秒數，0 表示沒有取得資料
+}
+
+// Label 回傳時段的時鐘時間，例如 "08:00"。
+func (s TrafficSlot) Label() string {
+	return s.Departure.In(taipeiLocation).Format("15:04")
+}
+
+// Minutes 回傳四捨五入的開車分鐘數。
+func (s TrafficSlot) Minutes() int {
+	return int(math.Round(float64(s.Seconds) / 60))
+}
+
+// TrafficProfile 為未來 24 小時各時段的預測開車時間，Slots 依時鐘時間排序。
+type TrafficProfile struct {
+	Origin      string
+	Destination string
+	Slots       []TrafficSlot
+}
+
+// Peak 回傳開車時間最長的時段，沒有任何資料時回傳 false。
+func (p TrafficProfile) Peak() (TrafficSlot, bool) {
+	return p.pick(func(a, b TrafficSlot) bool { return a.Seconds > b.Seconds })
+}
+
+// Best 回傳開車時間最短的時段，沒有任何資料時回傳 false。
+func (p TrafficProfile) Best() (TrafficSlot, bool) {
+	return p.pick(func(a, b TrafficSlot) bool { return a.Seconds < b.Seconds })
+}
+
+func (p TrafficProfile) pick(better func(a, b TrafficSlot) bool) (TrafficSlot, bool) {
+	var picked TrafficSlot
+	found := false
+	for _, slot := range p.Slots {
+		if slot.Seconds == 0 {
+			continue
+		}
+		if !found || better(slot, picked) {
+			picked, found = slot, true
+		}
+	}
+	return picked, found
+}
+
+func (p TrafficProfile) String() string {
+	lines := []string{fmt.Sprintf("起點: %s\n終點: %s", p.Origin, p.Destination)}
+	peak, ok := p.Peak()
+	if !ok {
+		return lines[0] + "\n\n無法獲取預測交通資訊"
+	}
+	best, _ := p.Best()
+	lines = append(lines,
+		fmt.Sprintf("預測高峰時段為: %s左右 (約 %d 分鐘)", peak.Label(), peak.Minutes()),
+		fmt.Sprintf("建議出發時段為: %s左右 (約 %d 分鐘)", best.Label(), best.Minutes()),
+		"",
+		"未來 24 小時各時段開車時間:",
+	)
+	for _, slot := range p.Slots {
+		switch {
+		case slot.Seconds == 0:
+			lines = append(lines, fmt.Sprintf("%s 無資料", slot.Label()))
+		case slot.Departure.Equal(peak.Departure):
+			lines = append(lines, fmt.Sprintf("%s %d 分鐘 (高峰)", slot.Label(), slot.Minutes()))
+		case slot.Departure.Equal(best.Departure):
+			lines = append(lines, fmt.Sprintf("%s %d 分鐘 (最快)", slot.Label(), slot.Minutes()))
+		default:
+			lines = append(lines, fmt.Sprintf("%s %d 分鐘", slot.Label(), slot.Minutes()))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// predictedTrafficSlots 回傳未來 24 小時內對齊整點的出發時段，依時鐘時間排序，
+// 今天已經過去的時段改為明天同一時間。
+func predictedTrafficSlots(now time.Time) []TrafficSlot {
+	now = now.In(taipeiLocation)
+	var slots []TrafficSlot
+	for hour := 0; hour < 24; hour += predictedTrafficSlotHours {
+		departure := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, taipeiLocation)
+		if !departure.After(now) {
+			departure = departure.AddDate(0, 0, 1)
+		}
+		slots = append(slots, TrafficSlot{Departure: departure})
+	}
+	return slots
+}
+
+// getPredictedTraffic 以固定數量的 worker 同時查詢各時段的開車時間，並在 predictedTrafficTimeout
+// 內回傳已取得的完整時段資料。找不到地點等查詢其他時段也不會改變的錯誤會立即停止並回傳；
+// 所有時段都失敗時回傳最後一個錯誤。
+func getPredictedTraffic(origin, destination string) (TrafficProfile, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), predictedTrafficTimeout)
+	defer cancel()
+
+	profile := TrafficProfile{
+		Origin:      origin,
+		Destination: destination,
+		Slots:       predictedTrafficSlots(time.Now()),
+	}
+
+	var (
+		mu       sync.Mutex
+		lastErr  error
+		fatalErr error
+		wg       sync.WaitGroup
+	)
+	jobs := make(chan int)
+	for w := 0; w < predictedTrafficWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				seconds, err := predictSlot(ctx, origin, destination, profile.Slots[i].Departure)
+				if err == nil {
+					// 每個 worker 只寫入自己負責的時段
+					profile.Slots[i].Seconds = seconds
+					continue
+				}
+				if errors.Is(ctx.Err(), context.Canceled) {
+					// 其他時段已遇到無法重試的錯誤
+					continue
+				}
+
+				log.Printf("Failed to predict traffic at %s: %v", profile.Slots[i].Label(), err)
+				mu.Lock()
+				lastErr = err
+				var directionsErr *DirectionsError
+				if errors.As(err, &directionsErr) && !directionsErr.Temporary() && fatalErr == nil {
+					fatalErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for i := range profile.Slots {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if fatalErr != nil {
+		return profile, fatalErr
+	}
+	if _, ok := profile.Peak(); !ok {
+		if lastErr == nil {
+			lastErr = &DirectionsError{Kind: DirectionsTimeout, Err: ctx.Err()}
+		}
+		return profile, lastErr
+	}
+	return profile, nil
+}
+
+// predictSlot 查詢指定出發時間考慮路況的開車秒數。
+func predictSlot(ctx context.Context, origin, destination string, departure time.Time) (int, error) {
+	directionsResponse, err := directionsProvider.Directions(ctx, DirectionsRequest{
+		Origin:        origin,
+		Destination:   destination,
+		Mode:          "driving",
+		DepartureTime: departure,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	leg, ok := directionsResponse.FirstLeg()
+	if !ok {
+		return 0, &DirectionsError{Kind: DirectionsNoRoute, Mode: "driving"}
+	}
+	// 沒有路況資料的地區只有一般開車時間
+	if leg.DurationInTraffic == nil {
+		return leg.Duration.Value, nil
+	}
+	return leg.DurationInTraffic.Value, nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetTrafficCondition(t *testing.T) {
+	useFakeDirections(t)
+
+	// 起訖點不含縣市名稱，不會觸發施工資料的查詢
+	condition, err := getTrafficCondition("台北車站", "台北101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if condition.Origin != "台北車站" || condition.Destination != "台北101" {
+		t.Errorf("condition = %+v", condition)
+	}
+	if condition.Regular.Value != 1200 || condition.InTraffic.Value < 1080 || condition.InTraffic.Value > 2160 {
+		t.Errorf("regular = %d, in traffic = %d", condition.Regular.Value, condition.InTraffic.Value)
+	}
+	if want := classifyCongestion(condition.Regular.Value, condition.InTraffic.Value); condition.Level != want {
+		t.Errorf("level = %v, want %v", condition.Level, want)
+	}
+
+	_, err = getTrafficCondition(fakeDirectionsUnknownPlace, "台北101")
+	var directionsErr *DirectionsError
+	if !errors.As(err, &directionsErr) || directionsErr.Kind != DirectionsNotFound {
+		t.Fatalf("err = %v, want DirectionsNotFound", err)
+	}
+	if got, want := directionsErrorMessage(err), "找不到起點，請輸入更完整的地址或地標名稱"; got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+}
+
+func TestGetBestRoute(t *testing.T) {
+	useFakeDirections(t)
+
+	flex, err := getBestRoute("台北車站", "台北101", "driving")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(flex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"台北車站", "台北101",
+		"1. 向東，朝忠孝西路一段前進",
+		"2. 稍微向右轉，繼續走市民大道",
+		"3. 向右轉，進入松仁路目的地在左側",
+		"4.5 公里, 13 分鐘",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("flex message does not contain %q: %s", want, body)
+		}
+	}
+
+	_, err = getBestRoute("台北車站", fakeDirectionsUnknownPlace, "walking")
+	if got, want := directionsErrorMessage(err), "找不到終點，請輸入更完整的地址或地標名稱"; got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+}
+
+func TestGetPredictedTraffic(t *testing.T) {
+	useFakeDirections(t)
+
+	profile, err := getPredictedTraffic("台北車站", "台北101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profile.Slots) != 24/predictedTrafficSlotHours {
+		t.Fatalf("got %d slots", len(profile.Slots))
+	}
+	for _, slot := range profile.Slots {
+		if want := 1200 * fakeTrafficFactor(slot.Departure) / 100; slot.Seconds != want {
+			t.Errorf("%s: %d seconds, want %d", slot.Label(), slot.Seconds, want)
+		}
+	}
+	// 尖峰倍率相同時取時鐘時間較早的時段
+	peak, _ := profile.Peak()
+	best, _ := profile.Best()
+	if peak.Label() != "08:00" || peak.Minutes() != 36 {
+		t.Errorf("peak = %s %d 分鐘, want 08:00 36 分鐘", peak.Label(), peak.Minutes())
+	}
+	if best.Label() != "00:00" || best.Minutes() != 18 {
+		t.Errorf("best = %s %d 分鐘, want 00:00 18 分鐘", best.Label(), best.Minutes())
+	}
+	if !strings.Contains(profile.String(), "預測高峰時段為: 08:00左右 (約 36 分鐘)") {
+		t.Errorf("reply = %q", profile.String())
+	}
+
+	// 找不到地點不會因其他時段而改變，立即回傳
+	_, err = getPredictedTraffic(fakeDirectionsUnknownPlace, "台北101")
+	var directionsErr *DirectionsError
+	if !errors.As(err, &directionsErr) || directionsErr.Kind != DirectionsNotFound {
+		t.Errorf("err = %v, want DirectionsNotFound", err)
+	}
+}
+
+func TestTrafficConditionWithoutLiveTraffic(t *testing.T) {
+	leg := DirectionsLeg{Duration: TextValue{Text: "20 分鐘", Value: 1200}}
+	condition := newTrafficCondition("花蓮車站", "太魯閣", leg)
+	if condition.Level != CongestionUnknown || condition.DelayMinutes() != 0 {
+		t.Fatalf("level = %v, delay = %d", condition.Level, condition.DelayMinutes())
+	}
+
+	reply := condition.String()
+	if !strings.Contains(reply, "目前無法取得即時路況資料") || !strings.Contains(reply, "平常開車時間:20 分鐘") {
+		t.Errorf("reply = %q", reply)
+	}
+	body, err := json.Marshal(trafficConditionBubble(condition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, notWant := range []string{"順暢", "與平常相同"} {
+		if strings.Contains(reply, notWant) || strings.Contains(string(body), notWant) {
+			t.Errorf("reply claims %q without live traffic: %q %s", notWant, reply, body)
+		}
+	}
+
+	leg.DurationInTraffic = &TextValue{Text: "21 分鐘", Value: 1260}
+	if condition := newTrafficCondition("花蓮車站", "太魯閣", leg); condition.Level != CongestionSmooth {
+		t.Errorf("level = %v, want %v", condition.Level, CongestionSmooth)
+	}
+}
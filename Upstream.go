@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 對外連線的預設值，可由 UPSTREAM_CONFIG 覆寫
+const (
+	defaultUpstreamTimeout    = 15 * time.Second
+	defaultUpstreamRetries    = 2
+	defaultUpstreamBackoff    = 500 * time.Millisecond
+	defaultUpstreamMaxBody    = 20 << 20
+	defaultUpstreamUserAgent  = "GolangMapsLineBot/1.0 (+https://github.com/donglin0202/GolangMapsLineBot)"
+	googleMapsUpstreamSource  = "google"
+	upstreamConfigEnvVariable = "UPSTREAM_CONFIG"
+)
+
+// UpstreamSourceConfig 為單一來源的連線設定。
+type UpstreamSourceConfig struct {
+	// ProxyURL 為 HTTP proxy，例如 http://proxy:3128
+	ProxyURL string `json:"proxyUrl,omitempty"`
+	// URLTemplate 為轉址服務的網址，{url} 替換為原網址，{escapedUrl} 替換為編碼後的原網址，
+	// 例如 "https://relay.example.com/?url={escapedUrl}"
+	URLTemplate string `json:"urlTemplate,omitempty"`
+	// Timeout 為單次請求的時間上限，例如 "30s"
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// UpstreamConfig 為所有對外連線的設定，以 JSON 寫在 UPSTREAM_CONFIG 環境變數或其指向的檔案。
+type UpstreamConfig struct {
+	UserAgent    string                          `json:"userAgent,omitempty"`
+	Timeout      string                          `json:"timeout,omitempty"`
+	Retries      *int                            `json:"retries,omitempty"`
+	MaxBodyBytes int64                           `json:"maxBodyBytes,omitempty"`
+	Sources      map[string]UpstreamSourceConfig `json:"sources,omitempty"`
+}
+
+// UpstreamStatusError 表示上游回應了非 200 的狀態碼。
+type UpstreamStatusError struct {
+	Source     string
+	StatusCode int
+}
+
+func (e *UpstreamStatusError) Error() string {
+	return fmt.Sprintf("%s HTTP 請求失敗，狀態碼: %d", e.Source, e.StatusCode)
+}
+
+// errResponseTooLarge 表示回應內容超過大小上限
+var errResponseTooLarge = errors.New("回應內容超過大小上限")
+
+// UpstreamClient 為 Traffic 與 Construction 共用的對外 HTTP client，
+// 處理逾時、可重試錯誤的退避重試、User-Agent、回應大小上限與各來源的 proxy。
+type UpstreamClient struct {
+	config  UpstreamConfig
+	timeout time.Duration
+	retries int
+	backoff time.Duration
+
+	mu      sync.Mutex
+	clients map[string]*http.Client // 依 HTTP proxy 網址共用 client
+}
+
+var upstream = NewUpstreamClient(loadUpstreamConfig())
+
+// loadUpstreamConfig 讀取 UPSTREAM_CONFIG，內容可為 JSON 或 JSON 檔案路徑。
+func loadUpstreamConfig() UpstreamConfig {
+	var config UpstreamConfig
+	raw := os.Getenv(upstreamConfigEnvVariable)
+	if raw != "" && raw[0] != '{' {
+		data, err := os.ReadFile(raw)
+		if err != nil {
+			log.Printf("讀取 %s 失敗: %v", upstreamConfigEnvVariable, err)
+			raw = ""
+		} else {
+			raw = string(data)
+		}
+	}
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &config); err != nil {
+			log.Printf("解析 %s 失敗: %v", upstreamConfigEnvVariable, err)
+		}
+	}
+	if os.Getenv("PROXY_URL") != "" {
+		log.Printf("PROXY_URL 已不再使用，請改以 %s 的 urlTemplate 設定轉址服務", upstreamConfigEnvVariable)
+	}
+	return config
+}
+
+// NewUpstreamClient 依設定建立 client，未設定的項目使用預設值。
+func NewUpstreamClient(config UpstreamConfig) *UpstreamClient {
+	u := &UpstreamClient{
+		config:  config,
+		timeout: defaultUpstreamTimeout,
+		retries: defaultUpstreamRetries,
+		backoff: defaultUpstreamBackoff,
+		clients: map[string]*http.Client{},
+	}
+	if d, err := time.ParseDuration(config.Timeout); err == nil && d > 0 {
+		u.timeout = d
+	}
+	if config.Retries != nil && *config.Retries >= 0 {
+		u.retries = *config.Retries
+	}
+	if u.config.UserAgent == "" {
+		u.config.UserAgent = defaultUpstreamUserAgent
+	}
+	if u.config.MaxBodyBytes <= 0 {
+		u.config.MaxBodyBytes = defaultUpstreamMaxBody
+	}
+	return u
+}
+
+// Get 以 GET 取得 URL 的內容。source 為設定中的來源名稱 (縣市名稱或 "google")，
+// header 可覆寫預設的標頭。連線錯誤、429 與 5xx 會以指數退避重試。
+func (u *UpstreamClient) Get(ctx context.Context, source, URL string, header http.Header) ([]byte, error) {
+	return u.request(ctx, http.MethodGet, source, URL, nil, header)
+}
+
+// PostForm 以 POST 送出表單並取得回應內容，用於 ASP.NET WebForms 的翻頁 postback。
+// 這類 postback 只用來讀取資料，因此與 Get 一樣會重試。
+func (u *UpstreamClient) PostForm(ctx context.Context, source, URL string, form url.Values, header http.Header) ([]byte, error) {
+	postHeader := http.Header{}
+	for key, values := range header {
+		postHeader[key] = values
+	}
+	postHeader.Set("Content-Type", "application/x-www-form-urlencoded")
+	return u.request(ctx, http.MethodPost, source, URL, []byte(form.Encode()), postHeader)
+}
+
+func (u *UpstreamClient) request(ctx context.Context, method, source, URL string, payload []byte, header http.Header) ([]byte, error) {
+	sourceConfig := u.config.Sources[source]
+	timeout := u.timeout
+	if d, err := time.ParseDuration(sourceConfig.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+	client, err := u.client(sourceConfig.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= u.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, lastErr
+			case <-time.After(u.backoff << (attempt - 1)):
+			}
+		}
+
+		body, retry, err := u.do(ctx, client, timeout, method, source, sourceConfig.requestURL(URL), payload, header)
+		if err == nil {
+			return body, nil
+		}
+		lastErr = err
+		if !retry || ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+func (u *UpstreamClient) do(ctx context.Context, client *http.Client, timeout time.Duration, method, source, URL string, payload []byte, header http.Header) (body []byte, retry bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, URL, reader)
+	if err != nil {
+		return nil, false, fmt.Errorf("建立請求失敗: %w", err)
+	}
+	req.Header.Set("User-Agent", u.config.UserAgent)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactURL(urlErr.URL)
+		}
+		return nil, true, fmt.Errorf("HTTP %s 失敗: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return nil, retry, &UpstreamStatusError{Source: source, StatusCode: resp.StatusCode}
+	}
+
+	body, err = io.ReadAll(io.LimitReader(resp.Body, u.config.MaxBodyBytes+1))
+	if err != nil {
+		return nil, true, fmt.Errorf("讀取回應失敗: %w", err)
+	}
+	if int64(len(body)) > u.config.MaxBodyBytes {
+		return nil, false, fmt.Errorf("%s %w (%d bytes)", source, errResponseTooLarge, u.config.MaxBodyBytes)
+	}
+	return body, false, nil
+}
+
+// requestURL 回傳實際請求的網址，沒有設定 URLTemplate 時即為原網址。
+func (c UpstreamSourceConfig) requestURL(URL string) string {
+	if c.URLTemplate == "" {
+		return URL
+	}
+	return strings.NewReplacer("{url}", URL, "{escapedUrl}", url.QueryEscape(URL)).Replace(c.URLTemplate)
+}
+
+// client 回傳使用指定 HTTP proxy 的 client，proxyURL 為空字串時不使用 proxy。
+func (u *UpstreamClient) client(proxyURL string) (*http.Client, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if client, ok := u.clients[proxyURL]; ok {
+		return client, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	if proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("proxy 設定錯誤: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	client := &http.Client{Transport: transport}
+	u.clients[proxyURL] = client
+	return client, nil
+}
+
+// redactURL 隱藏網址中的 API key，避免寫入 log。
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := u.Query()
+	if query.Get("key") == "" {
+		return rawURL
+	}
+	query.Set("key", "REDACTED")
+	u.RawQuery = query.Encode()
+	return u.String()
+}
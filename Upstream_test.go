@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestUpstream 建立退避時間很短的 client，避免測試等待重試。
+func newTestUpstream(config UpstreamConfig) *UpstreamClient {
+	client := NewUpstreamClient(config)
+	client.backoff = time.Millisecond
+	return client
+}
+
+func TestUpstreamRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int // 依序回應的狀態碼，之後都回應 200
+		requests int32
+		status   int // 預期最後的錯誤狀態碼，0 表示成功
+	}{
+		{"503 then success", []int{503, 503}, 3, 0},
+		{"429 then success", []int{429}, 2, 0},
+		{"5xx exhausts retries", []int{500, 502, 503, 504}, 3, 503},
+		{"4xx is not retried", []int{404}, 1, 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&requests, 1)
+				if int(n) <= len(tt.statuses) {
+					w.WriteHeader(tt.statuses[n-1])
+					return
+				}
+				w.Write([]byte("ok"))
+			}))
+			defer server.Close()
+
+			body, err := newTestUpstream(UpstreamConfig{}).Get(context.Background(), "台北市", server.URL, nil)
+			if got := atomic.LoadInt32(&requests); got != tt.requests {
+				t.Errorf("requests = %d, want %d", got, tt.requests)
+			}
+			if tt.status == 0 {
+				if err != nil || string(body) != "ok" {
+					t.Errorf("Get = %q, %v", body, err)
+				}
+				return
+			}
+			var statusErr *UpstreamStatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.status {
+				t.Errorf("err = %v, want status %d", err, tt.status)
+			}
+		})
+	}
+}
+
+func TestUpstreamMaxBodyBytes(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(strings.Repeat("x", 11)))
+	}))
+	defer server.Close()
+
+	_, err := newTestUpstream(UpstreamConfig{MaxBodyBytes: 10}).Get(context.Background(), "台北市", server.URL, nil)
+	if !errors.Is(err, errResponseTooLarge) {
+		t.Errorf("err = %v, want errResponseTooLarge", err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, oversized responses should not be retried", requests)
+	}
+
+	body, err := newTestUpstream(UpstreamConfig{MaxBodyBytes: 11}).Get(context.Background(), "台北市", server.URL, nil)
+	if err != nil || len(body) != 11 {
+		t.Errorf("Get = %d bytes, %v", len(body), err)
+	}
+}
+
+func TestUpstreamRedactsAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	URL := server.URL + "/maps/api/directions/json?origin=a&key=secret-key"
+	server.Close()
+
+	retries := 0
+	_, err := newTestUpstream(UpstreamConfig{Retries: &retries}).Get(context.Background(), googleMapsUpstreamSource, URL, nil)
+	if err == nil {
+		t.Fatal("expected a connection error")
+	}
+	if strings.Contains(err.Error(), "secret-key") || !strings.Contains(err.Error(), "key=REDACTED") {
+		t.Errorf("err = %v, want the key redacted", err)
+	}
+}
+
+func TestUpstreamURLTemplate(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query().Get("url")
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := newTestUpstream(UpstreamConfig{Sources: map[string]UpstreamSourceConfig{
+		"高雄市": {URLTemplate: server.URL + "/?url={escapedUrl}"},
+	}})
+	original := "https://example.gov.tw/api?a=1&b=2"
+	if _, err := client.Get(context.Background(), "高雄市", original, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got != original {
+		t.Errorf("relay received url=%q, want %q", got, original)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// 作為起點時讓假伺服器模擬各種失敗的地點名稱
+const (
+	fakeDirectionsUnknownPlace = "不存在的地點" // 回應 NOT_FOUND
+	fakeDirectionsSlowPlace    = "逾時的地點"  // 超過 client 的逾時才回應
+	fakeDirectionsBrokenPlace  = "故障的地點"  // 回應 503
+	fakeDirectionsGarbledPlace = "亂碼的地點"  // 回應無法解析的 JSON
+)
+
+// 測試時 upstream client 等待假伺服器回應的時間上限
+const fakeDirectionsTimeout = 200 * time.Millisecond
+
+// 假伺服器回傳的固定路線，%s 依序為起點、終點，%d 依序為不塞車與塞車時的秒數
+const fakeDirectionsRouteJSON = `{
+  "status": "OK",
+  "geocoded_waypoints": [
+    {"geocoder_status": "OK", "place_id": "ChIJ-fake-origin", "types": ["street_address"]},
+    {"geocoder_status": "OK", "place_id": "ChIJ-fake-destination", "types": ["street_address"]}
+  ],
+  "routes": [
+    {
+      "summary": "市民大道",
+      "copyrights": "Map data ©2024",
+      "warnings": [],
+      "overview_polyline": {"points": "o~bwCo~ncV"},
+      "legs": [
+        {
+          "start_address": %s,
+          "end_address": %s,
+          "start_location": {"lat": 25.0478, "lng": 121.5170},
+          "end_location": {"lat": 25.0340, "lng": 121.5645},
+          "distance": {"text": "5.8 公里", "value": 5800},
+          "duration": {"text": "%s", "value": %d},
+          "duration_in_traffic": {"text": "%s", "value": %d},
+          "steps": [
+            {
+              "html_instructions": "向<b>東</b>，朝<b>忠孝西路一段</b>前進",
+              "travel_mode": "DRIVING",
+              "distance": {"text": "0.3 公里", "value": 300},
+              "duration": {"text": "1 分鐘", "value": 60},
+              "start_location": {"lat": 25.0478, "lng": 121.5170},
+              "end_location": {"lat": 25.0461, "lng": 121.5200}
+            },
+            {
+              "html_instructions": "稍微向<b>右</b>轉，繼續走<b>市民大道</b>",
+              "travel_mode": "DRIVING",
+              "maneuver": "turn-slight-right",
+              "distance": {"text": "4.5 公里", "value": 4500},
+              "duration": {"text": "13 分鐘", "value": 780},
+              "start_location": {"lat": 25.0461, "lng": 121.5200},
+              "end_location": {"lat": 25.0440, "lng": 121.5600}
+            },
+            {
+              "html_instructions": "向<b>右</b>轉，進入<b>松仁路</b><div style=\"font-size:0.9em\">目的地在左側</div>",
+              "travel_mode": "DRIVING",
+              "maneuver": "turn-right",
+              "distance": {"text": "1.0 公里", "value": 1000},
+              "duration": {"text": "6 分鐘", "value": 360},
+              "start_location": {"lat": 25.0440, "lng": 121.5600},
+              "end_location": {"lat": 25.0340, "lng": 121.5645}
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}`
+
+// 找不到地點時的回應，%s 依序為起點與終點的 geocoder_status
+const fakeDirectionsNotFoundJSON = `{
+  "status": "NOT_FOUND",
+  "geocoded_waypoints": [
+    {"geocoder_status": "%s"},
+    {"geocoder_status": "%s"}
+  ],
+  "routes": []
+}`
+
+// useFakeDirections 讓三個路況指令改用本機的假 Directions API，並換成逾時較短、不重試的
+// upstream client，測試結束時還原。
+func useFakeDirections(t *testing.T) {
+	t.Helper()
+	server := newFakeDirectionsServer()
+	provider, client := directionsProvider, upstream
+	retries := 0
+	directionsProvider = newGoogleDirections(server.URL, "test-key")
+	upstream = NewUpstreamClient(UpstreamConfig{Timeout: fakeDirectionsTimeout.String(), Retries: &retries})
+	t.Cleanup(func() {
+		directionsProvider, upstream = provider, client
+		server.Close()
+	})
+}
+
+// newFakeDirectionsServer 啟動本機的假 Directions API，回傳固定的路線，
+// 塞車時間隨出發時間在上下班尖峰變長。起點為 fakeDirections*Place 時模擬對應的失敗。
+func newFakeDirectionsServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		origin, destination := params.Get("origin"), params.Get("destination")
+
+		switch origin {
+		case fakeDirectionsSlowPlace:
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * fakeDirectionsTimeout):
+			}
+			return
+		case fakeDirectionsBrokenPlace:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case fakeDirectionsGarbledPlace:
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, "<html><body>Service Unavailable</body></html>")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+		if origin == fakeDirectionsUnknownPlace || destination == fakeDirectionsUnknownPlace {
+			status := func(place string) string {
+				if place == fakeDirectionsUnknownPlace {
+					return "ZERO_RESULTS"
+				}
+				return "OK"
+			}
+			fmt.Fprintf(w, fakeDirectionsNotFoundJSON, status(origin), status(destination))
+			return
+		}
+
+		departure := time.Now()
+		if unix, err := strconv.ParseInt(params.Get("departure_time"), 10, 64); err == nil {
+			departure = time.Unix(unix, 0)
+		}
+		regular := 1200
+		traffic := regular * fakeTrafficFactor(departure) / 100
+		quote := func(s string) string {
+			b, _ := json.Marshal(s)
+			return string(b)
+		}
+		fmt.Fprintf(w, fakeDirectionsRouteJSON,
+			quote(origin), quote(destination),
+			fakeDurationText(regular), regular,
+			fakeDurationText(traffic), traffic)
+	}))
+}
+
+// fakeTrafficFactor 回傳出發時間的塞車倍率 (百分比)，模擬上下班尖峰。
+func fakeTrafficFactor(departure time.Time) int {
+	switch hour := departure.In(taipeiLocation).Hour(); {
+	case hour >= 7 && hour < 9, hour >= 17 && hour < 19:
+		return 180
+	case hour >= 9 && hour < 17:
+		return 120
+	case hour >= 19 && hour < 22:
+		return 110
+	}
+	return 90
+}
+
+// fakeDurationText 以 Google 的中文格式顯示秒數，例如 "1 小時 5 分鐘"。
+func fakeDurationText(seconds int) string {
+	minutes := (seconds + 30) / 60
+	if minutes < 60 {
+		return fmt.Sprintf("%d 分鐘", minutes)
+	}
+	return fmt.Sprintf("%d 小時 %d 分鐘", minutes/60, minutes%60)
+}
@@ -2,11 +2,15 @@ module GolangMapsLineBot
 
 go 1.23.2
 
-require github.com/line/line-bot-sdk-go/v8 v8.9.0
+require (
+	github.com/PuerkitoBio/goquery v1.10.0
+	github.com/line/line-bot-sdk-go/v8 v8.9.0
+	go.etcd.io/bbolt v1.4.0
+	golang.org/x/text v0.20.0
+)
 
 require (
-	github.com/PuerkitoBio/goquery v1.10.0 // indirect
 	github.com/andybalholm/cascadia v1.3.2 // indirect
 	golang.org/x/net v0.31.0 // indirect
-	golang.org/x/text v0.20.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
 )
@@ -13,12 +13,15 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/line/line-bot-sdk-go/v8/linebot"
 	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
@@ -48,8 +51,30 @@ var Instruction = `1. 即時路況查詢
 指令格式:
 道路施工查詢
 [縣市名稱]
+[行政區 路名(可省略)]
+[今天, 本週, 未來7天 或日期(可省略)]
+[搶修, 銑鋪, 人手孔 等類別(可省略)]
 
-5. 指令查詢
+5. 附近施工查詢
+分享位置訊息即可查詢附近的道路施工
+
+6. 施工訂閱
+指令格式:
+施工訂閱
+[縣市名稱]
+[行政區 路名]
+查詢訂閱: 訂閱清單
+取消訂閱格式:
+取消訂閱
+[訂閱編號]
+
+7. 施工統計
+指令格式:
+施工統計
+[縣市名稱]
+[近30天, 近90天, 本月 或 今年(可省略，預設近90天)]
+
+8. 指令查詢
 指令格式:
 指令`
 
@@ -57,11 +82,25 @@ func main() {
 	var err error
 	bot, err = linebot.New(os.Getenv("ChannelSecret"), os.Getenv("ChannelAccessToken"))
 	log.Println("Bot:", bot, " err:", err)
+	constructionCache.OnUpdate(notifySubscribers)
+	archivePath := os.Getenv("CONSTRUCTION_ARCHIVE_FILE")
+	if archivePath == "" {
+		archivePath = defaultConstructionArchiveFile
+	}
+	if constructionArchive, err = OpenConstructionArchive(archivePath); err != nil {
+		log.Print(err)
+	} else {
+		constructionCache.OnUpdate(constructionArchive.Record)
+	}
+	constructionCache.Start(context.Background())
 	http.HandleFunc("/callback", callbackHandler)
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	})
+	http.HandleFunc("/api/construction", constructionExportHandler)
+	http.HandleFunc("/admin/construction/status", adminConstructionStatusHandler)
+	http.HandleFunc("/admin/directions/status", adminDirectionsStatusHandler)
 	port := os.Getenv("PORT")
 	addr := fmt.Sprintf(":%s", port)
 	http.ListenAndServe(addr, nil)
@@ -85,7 +124,10 @@ func callbackHandler(w http.ResponseWriter, r *http.Request) {
 			switch message := e.Message.(type) {
 			// Handle only on text message
 			case webhook.TextMessageContent:
-				handleTextMessage(bot, e.ReplyToken, message.Text)
+				handleTextMessage(bot, e.ReplyToken, pushTarget(e.Source), message.Text)
+
+			case webhook.LocationMessageContent:
+				handleLocationMessage(bot, e.ReplyToken, message)
 
 			default:
 				if _, err = bot.ReplyMessage(e.ReplyToken, linebot.NewTextMessage(InstructionErrorMsg)).Do(); err != nil {
@@ -97,24 +139,100 @@ func callbackHandler(w http.ResponseWriter, r *http.Request) {
 			log.Printf("message: Got followed event")
 		case webhook.PostbackEvent:
 			data := e.Postback.Data
-			log.Printf("Unknown message: Got postback: " + data)
+			query, offset, err := ParseConstructionPostback(data)
+			switch {
+			case errors.Is(err, errNotConstructionPostback):
+				log.Printf("Unknown message: Got postback: " + data)
+			case err != nil:
+				if _, err := bot.ReplyMessage(e.ReplyToken, linebot.NewTextMessage(constructionErrorMessage(err))).Do(); err != nil {
+					log.Print(err)
+				}
+			default:
+				replyConstruction(bot, e.ReplyToken, query, offset)
+			}
 		case webhook.BeaconEvent:
 			log.Printf("Got beacon: " + e.Beacon.Hwid)
 		}
 	}
 }
 
-func replyWithFlexMessage(bot *linebot.Client, replyToken string, flex map[string]interface{}) error {
-	flexJSON, err := json.Marshal(flex)
-	flexContainer, err := linebot.UnmarshalFlexMessageJSON(flexJSON)
+// adminAuthorized 檢查請求是否帶有 ADMIN_TOKEN，未設定 ADMIN_TOKEN 時停用管理端點。
+func adminAuthorized(w http.ResponseWriter, r *http.Request) bool {
+	token := os.Getenv("ADMIN_TOKEN")
+	if token == "" {
+		http.NotFound(w, r)
+		return false
+	}
+	if r.Header.Get("Authorization") != "Bearer "+token {
+		w.WriteHeader(http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// adminConstructionStatusHandler 列出各來源狀態，schemaDrift 為 true 的來源需檢查網站是否改版。
+func adminConstructionStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if !adminAuthorized(w, r) {
+		return
+	}
+	var drifted []SourceStatus
+	statuses := constructionCache.Status()
+	for _, status := range statuses {
+		if status.SchemaDrift {
+			drifted = append(drifted, status)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sources":     statuses,
+		"schemaDrift": drifted,
+	})
+}
+
+// adminDirectionsStatusHandler 列出 Directions API 的 key 與配額警示。
+func adminDirectionsStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if !adminAuthorized(w, r) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(directionsAlerts.List())
+}
+
+func replyWithFlexMessage(bot *linebot.Client, replyToken string, altText string, flex map[string]interface{}) error {
+	message, err := newFlexMessage(altText, flex)
 	if err != nil {
 		return err
 	}
-	_, err = bot.ReplyMessage(replyToken, linebot.NewFlexMessage("最佳路線", flexContainer)).Do()
+	_, err = bot.ReplyMessage(replyToken, message).Do()
 	return err
 }
 
-func handleTextMessage(bot *linebot.Client, replyToken string, text string) {
+func newFlexMessage(altText string, flex map[string]interface{}) (*linebot.FlexMessage, error) {
+	flexJSON, err := json.Marshal(flex)
+	if err != nil {
+		return nil, err
+	}
+	flexContainer, err := linebot.UnmarshalFlexMessageJSON(flexJSON)
+	if err != nil {
+		return nil, err
+	}
+	return linebot.NewFlexMessage(truncateRunes(altText, flexAltTextLimit), flexContainer), nil
+}
+
+// pushTarget 回傳事件來源可用來推播的 ID，群組與聊天室推播到整個群組。
+func pushTarget(source webhook.SourceInterface) string {
+	switch s := source.(type) {
+	case webhook.UserSource:
+		return s.UserId
+	case webhook.GroupSource:
+		return s.GroupId
+	case webhook.RoomSource:
+		return s.RoomId
+	}
+	return ""
+}
+
+func handleTextMessage(bot *linebot.Client, replyToken string, target string, text string) {
 	lines := strings.Split(text, "\n")
 	function := strings.TrimSpace(lines[0])
 
@@ -132,9 +250,16 @@ func handleTextMessage(bot *linebot.Client, replyToken string, text string) {
 		}
 		origin := strings.TrimSpace(lines[1])
 		destination := strings.TrimSpace(lines[2])
-		TrafficCondition := getTrafficCondition(origin, destination)
-		reply := fmt.Sprintf("起點: %s\n終點: %s\n\n%s", origin, destination, TrafficCondition)
-		if _, err := bot.ReplyMessage(replyToken, linebot.NewTextMessage(reply)).Do(); err != nil {
+		condition, err := getTrafficCondition(origin, destination)
+		if err != nil {
+			log.Print(err)
+			reply := fmt.Sprintf("起點: %s\n終點: %s\n\n%s", origin, destination, directionsErrorMessage(err))
+			if _, err := bot.ReplyMessage(replyToken, linebot.NewTextMessage(reply)).Do(); err != nil {
+				log.Print(err)
+			}
+			return
+		}
+		if err := replyWithFlexMessage(bot, replyToken, condition.String(), trafficConditionBubble(condition)); err != nil {
 			log.Print(err)
 		}
 
@@ -163,8 +288,12 @@ func handleTextMessage(bot *linebot.Client, replyToken string, text string) {
 			}
 			return
 		}
-		bestRoute := getBestRoute(origin, destination, mode)
-		if err := replyWithFlexMessage(bot, replyToken, bestRoute); err != nil {
+		bestRoute, err := getBestRoute(origin, destination, mode)
+		if err != nil {
+			log.Print(err)
+			bestRoute = createErrorFlexMessage(directionsErrorMessage(err))
+		}
+		if err := replyWithFlexMessage(bot, replyToken, "最佳路線", bestRoute); err != nil {
 			log.Print(err)
 		}
 
@@ -177,19 +306,46 @@ func handleTextMessage(bot *linebot.Client, replyToken string, text string) {
 		}
 		origin := strings.TrimSpace(lines[1])
 		destination := strings.TrimSpace(lines[2])
-		reply := fmt.Sprintf(getPredictedTraffic(origin, destination))
+		profile, err := getPredictedTraffic(origin, destination)
+		reply := profile.String()
+		if err != nil {
+			log.Print(err)
+			reply = fmt.Sprintf("起點: %s\n終點: %s\n\n%s", origin, destination, directionsErrorMessage(err))
+		}
 		if _, err := bot.ReplyMessage(replyToken, linebot.NewTextMessage(reply)).Do(); err != nil {
 			log.Print(err)
 		}
 	case "道路施工查詢":
-		if len(lines) != 2 {
+		if len(lines) < 2 || len(lines) > 5 {
+			if _, err := bot.ReplyMessage(replyToken, linebot.NewTextMessage("指令格式錯誤，請重新輸入指令，支援指令格式為:\n\n"+Instruction)).Do(); err != nil {
+				log.Print(err)
+			}
+			return
+		}
+		if !resolveCountyLine(bot, replyToken, lines, 1) {
+			return
+		}
+		replyConstruction(bot, replyToken, ParseConstructionQuery(lines[1:]), 0)
+	case "施工統計":
+		if len(lines) < 2 || len(lines) > 3 {
 			if _, err := bot.ReplyMessage(replyToken, linebot.NewTextMessage("指令格式錯誤，請重新輸入指令，支援指令格式為:\n\n"+Instruction)).Do(); err != nil {
 				log.Print(err)
 			}
 			return
 		}
-		target := strings.TrimSpace(lines[1])
-		reply := GetConstruction(target)
+		if !resolveCountyLine(bot, replyToken, lines, 1) {
+			return
+		}
+		period := defaultConstructionTrendPeriod
+		if len(lines) == 3 {
+			period = strings.TrimSpace(lines[2])
+		}
+		replyConstructionStatistics(bot, replyToken, lines[1], period)
+	case "施工訂閱", "訂閱清單", "取消訂閱":
+		if function == "施工訂閱" && len(lines) >= 2 && !resolveCountyLine(bot, replyToken, lines, 1) {
+			return
+		}
+		reply := handleSubscriptionCommand(function, target, lines[1:])
 		if _, err := bot.ReplyMessage(replyToken, linebot.NewTextMessage(reply)).Do(); err != nil {
 			log.Print(err)
 		}
@@ -199,3 +355,145 @@ func handleTextMessage(bot *linebot.Client, replyToken string, text string) {
 		}
 	}
 }
+
+// resolveCountyLine 將指令第 index 行的縣市轉為標準名稱。縣市有歧義時以 quick reply
+// 讓使用者選擇，選項會以完整指令重新送出；無法辨識時回覆錯誤。兩種情況都回傳 false。
+func resolveCountyLine(bot *linebot.Client, replyToken string, lines []string, index int) bool {
+	input := strings.TrimSpace(lines[index])
+	counties := ResolveCounty(input)
+	if len(counties) == 1 {
+		lines[index] = counties[0]
+		return true
+	}
+
+	var message *linebot.TextMessage
+	if len(counties) == 0 {
+		message = linebot.NewTextMessage(fmt.Sprintf("無法辨識縣市「%s」，請輸入例如 台北市、新竹縣 等縣市名稱", input))
+	} else {
+		var buttons []*linebot.QuickReplyButton
+		for _, county := range counties {
+			choice := append([]string{}, lines...)
+			choice[index] = county
+			buttons = append(buttons, linebot.NewQuickReplyButton("", linebot.NewMessageAction(county, strings.Join(choice, "\n"))))
+		}
+		message = linebot.NewTextMessage(fmt.Sprintf("「%s」可能是以下縣市，請選擇:", input))
+		message.WithQuickReplies(linebot.NewQuickReplyItems(buttons...))
+	}
+	if _, err := bot.ReplyMessage(replyToken, message).Do(); err != nil {
+		log.Print(err)
+	}
+	return false
+}
+
+// replyConstruction 以 Flex carousel 回覆一頁施工查詢結果，純文字版本作為 altText。
+func replyConstruction(bot *linebot.Client, replyToken string, query ConstructionQuery, offset int) {
+	page, err := QueryConstruction(query, offset)
+	if err != nil {
+		log.Print(err)
+		if _, err := bot.ReplyMessage(replyToken, linebot.NewTextMessage(constructionErrorMessage(err))).Do(); err != nil {
+			log.Print(err)
+		}
+		return
+	}
+
+	if len(page.Cases) == 0 {
+		if _, err := bot.ReplyMessage(replyToken, linebot.NewTextMessage(page.String())).Do(); err != nil {
+			log.Print(err)
+		}
+		return
+	}
+	if err := replyWithFlexMessage(bot, replyToken, page.String(), constructionCarousel(page)); err != nil {
+		log.Print(err)
+	}
+}
+
+// replyConstructionStatistics 回覆歷史資料的施工統計，縣市另有統計資料集時一併以文字回覆。
+func replyConstructionStatistics(bot *linebot.Client, replyToken string, county string, period string) {
+	var messages []linebot.SendingMessage
+	if constructionArchive != nil {
+		trend, err := constructionArchive.Trend(county, period, time.Now())
+		switch {
+		case err != nil:
+			log.Print(err)
+			messages = append(messages, linebot.NewTextMessage("無法取得施工統計，期間請輸入 近30天、近90天、本月 或 今年"))
+		case trend.Total == 0:
+			messages = append(messages, linebot.NewTextMessage(trend.String()))
+		default:
+			message, err := newFlexMessage(trend.String(), constructionTrendBubble(trend))
+			if err != nil {
+				log.Print(err)
+				messages = append(messages, linebot.NewTextMessage(trend.String()))
+			} else {
+				messages = append(messages, message)
+			}
+		}
+	}
+	if _, ok := constructionStatisticsSources[county]; ok || len(messages) == 0 {
+		messages = append(messages, linebot.NewTextMessage(GetConstructionStatistics(county)))
+	}
+	if _, err := bot.ReplyMessage(replyToken, messages...).Do(); err != nil {
+		log.Print(err)
+	}
+}
+
+func handleLocationMessage(bot *linebot.Client, replyToken string, message webhook.LocationMessageContent) {
+	ctx, cancel := context.WithTimeout(context.Background(), nearbyConstructionTimeout)
+	defer cancel()
+
+	origin := LatLng{Lat: message.Latitude, Lng: message.Longitude}
+	radius := nearbyRadius()
+	result, err := findNearbyConstruction(ctx, origin, message.Address, radius)
+	var reply string
+	switch {
+	case err != nil:
+		log.Print(err)
+		reply = constructionErrorMessage(err)
+	case result.Source == nil:
+		reply = "無法判斷此位置所在的縣市，請重新分享位置"
+	case len(result.Cases) == 0:
+		reply = fmt.Sprintf("附近 %s 內目前沒有施工資料", formatDistance(radius))
+		if notice := result.partialNotice(); notice != "" {
+			reply += "\n\n" + notice
+		}
+	}
+	if reply != "" {
+		if _, err := bot.ReplyMessage(replyToken, linebot.NewTextMessage(reply)).Do(); err != nil {
+			log.Print(err)
+		}
+		return
+	}
+
+	// 一次回覆最多 5 則訊息，第一則為列表，其餘為前幾筆的位置訊息
+	const locationMessageLimit = 4
+	reply = fmt.Sprintf("附近 %s 內共有 %d 筆施工\n\n", formatDistance(radius), len(result.Cases))
+	var locations []linebot.SendingMessage
+	for idx, n := range result.Cases {
+		if idx < constructionReplyLimit {
+			reply += fmt.Sprintf("距離: %s\n%s\n", formatDistance(n.Distance), n.Case.String())
+		}
+		if idx < locationMessageLimit {
+			title := n.Case.Reason
+			if title == "" {
+				title = "道路施工"
+			}
+			// 位置訊息的標題與地址最多 100 字
+			locations = append(locations, linebot.NewLocationMessage(
+				truncateRunes(title, 100), truncateRunes(n.Case.Location, 100), n.Position.Lat, n.Position.Lng))
+		}
+	}
+	if notice := result.partialNotice(); notice != "" {
+		reply += "\n" + notice
+	}
+	messages := append([]linebot.SendingMessage{linebot.NewTextMessage(strings.TrimSpace(reply))}, locations...)
+	if _, err := bot.ReplyMessage(replyToken, messages...).Do(); err != nil {
+		log.Print(err)
+	}
+}
+
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}